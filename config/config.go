@@ -0,0 +1,460 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pelletier/go-toml/v2"
+	"gopkg.in/yaml.v3"
+)
+
+// Config holds every runtime setting of the service. It is built from
+// defaults, then an optional YAML or TOML file, then environment variables,
+// with later layers overriding earlier ones.
+type Config struct {
+	Server   ServerConfig   `yaml:"server"`
+	Database DatabaseConfig `yaml:"database"`
+	Auth     AuthConfig     `yaml:"auth"`
+	Admin    AdminConfig    `yaml:"admin"`
+	Workers  WorkersConfig  `yaml:"workers"`
+	Geo      GeoConfig      `yaml:"geo"`
+	Mail     MailConfig     `yaml:"mail"`
+	Storage  StorageConfig  `yaml:"storage"`
+}
+
+type ServerConfig struct {
+	Port string `yaml:"port"`
+	// Externally reachable base URL, used for links in emails and feeds
+	PublicURL       string        `yaml:"public_url"`
+	ShutdownTimeout time.Duration `yaml:"shutdown_timeout"`
+	// How long /readyz reports not-ready before the server stops accepting connections
+	DrainDelay  time.Duration `yaml:"drain_delay"`
+	CORSOrigins []string      `yaml:"cors_origins"`
+}
+
+type DatabaseConfig struct {
+	URL         string   `yaml:"url"`
+	ReplicaURLs []string `yaml:"replica_urls"`
+
+	MaxOpenConns    int           `yaml:"max_open_conns"`
+	MaxIdleConns    int           `yaml:"max_idle_conns"`
+	ConnMaxLifetime time.Duration `yaml:"conn_max_lifetime"`
+	ConnMaxIdleTime time.Duration `yaml:"conn_max_idle_time"`
+
+	// Startup connection attempts, doubling the backoff after each failure
+	ConnectRetries int           `yaml:"connect_retries"`
+	ConnectBackoff time.Duration `yaml:"connect_backoff"`
+
+	// Upper bound for every statement and for the database work done by a
+	// single request
+	StatementTimeout time.Duration `yaml:"statement_timeout"`
+}
+
+type AuthConfig struct {
+	UserSecret      string        `yaml:"user_secret"`
+	CompanySecret   string        `yaml:"company_secret"`
+	UserTokenTTL    time.Duration `yaml:"user_token_ttl"`
+	CompanyTokenTTL time.Duration `yaml:"company_token_ttl"`
+	CookieDomain    string        `yaml:"cookie_domain"`
+	CookieSecure    bool          `yaml:"cookie_secure"`
+	CookieSameSite  string        `yaml:"cookie_same_site"`
+}
+
+type AdminConfig struct {
+	APIKey string `yaml:"api_key"`
+}
+
+type MailConfig struct {
+	// Messages are only logged when no SMTP host is set
+	SMTPHost string `yaml:"smtp_host"`
+	SMTPPort int    `yaml:"smtp_port"`
+	Username string `yaml:"username"`
+	Password string `yaml:"password"`
+	From     string `yaml:"from"`
+}
+
+type StorageConfig struct {
+	// Where uploaded documents are kept: local or s3
+	Backend  string   `yaml:"backend"`
+	LocalDir string   `yaml:"local_dir"`
+	S3       S3Config `yaml:"s3"`
+	// Key for signing download links; derived from the JWT secrets when empty
+	SigningKey string `yaml:"signing_key"`
+	// How long a signed download link stays valid
+	DownloadURLTTL time.Duration `yaml:"download_url_ttl"`
+	// Largest accepted upload, in bytes
+	MaxUploadSize int `yaml:"max_upload_size"`
+}
+
+// S3Config points at any S3-compatible object store (AWS S3, MinIO, R2, ...)
+type S3Config struct {
+	Endpoint  string `yaml:"endpoint"`
+	Region    string `yaml:"region"`
+	Bucket    string `yaml:"bucket"`
+	AccessKey string `yaml:"access_key"`
+	SecretKey string `yaml:"secret_key"`
+	// Address the bucket as endpoint/bucket rather than bucket.endpoint
+	PathStyle bool `yaml:"path_style"`
+}
+
+type GeoConfig struct {
+	// Optional CSV gazetteer replacing the bundled one, used to geocode city names offline
+	GazetteerFile string `yaml:"gazetteer_file"`
+}
+
+type WorkersConfig struct {
+	// How often published jobs past their expiry date are moved to expired
+	JobExpiryInterval time.Duration `yaml:"job_expiry_interval"`
+	// How often saved searches are matched against newly published jobs
+	JobAlertInterval time.Duration `yaml:"job_alert_interval"`
+}
+
+// Default returns the configuration used when nothing else is set
+func Default() Config {
+	return Config{
+		Server: ServerConfig{
+			Port:            "8080",
+			PublicURL:       "http://localhost:8080",
+			ShutdownTimeout: 5 * time.Second,
+			DrainDelay:      5 * time.Second,
+			CORSOrigins:     []string{"http://localhost:3000"},
+		},
+		Database: DatabaseConfig{
+			MaxOpenConns:     25,
+			MaxIdleConns:     10,
+			ConnMaxLifetime:  30 * time.Minute,
+			ConnMaxIdleTime:  5 * time.Minute,
+			ConnectRetries:   5,
+			ConnectBackoff:   time.Second,
+			StatementTimeout: 10 * time.Second,
+		},
+		Auth: AuthConfig{
+			UserTokenTTL:    24 * time.Hour,
+			CompanyTokenTTL: 24 * time.Hour,
+			CookieSameSite:  "lax",
+		},
+		Mail: MailConfig{
+			SMTPPort: 587,
+			From:     "Workly <no-reply@workly.dev>",
+		},
+		Workers: WorkersConfig{
+			JobExpiryInterval: time.Minute,
+			JobAlertInterval:  time.Minute,
+		},
+		Storage: StorageConfig{
+			Backend:        "local",
+			LocalDir:       "uploads",
+			S3:             S3Config{Region: "us-east-1"},
+			DownloadURLTTL: 15 * time.Minute,
+			MaxUploadSize:  5 << 20,
+		},
+	}
+}
+
+// Scope selects the settings a binary needs, and so which ones are validated
+type Scope int
+
+const (
+	// ScopeServer is the API server, which uses every setting
+	ScopeServer Scope = iota
+	// ScopeDatabase is a tool that only talks to the database, such as
+	// migrate and seed
+	ScopeDatabase
+)
+
+// Files tried in order when no config file is named
+var defaultFiles = []string{"config.yaml", "config.yml", "config.toml"}
+
+// Load builds the configuration and validates the settings scope needs. path
+// is an optional YAML or TOML file, told apart by its extension; when empty
+// the CONFIG_FILE environment variable is used, and a missing default file is
+// not an error.
+func Load(path string, scope Scope) (*Config, error) {
+	cfg := Default()
+
+	if path == "" {
+		path = os.Getenv("CONFIG_FILE")
+	}
+	if path != "" {
+		if err := cfg.loadFile(path, true); err != nil {
+			return nil, err
+		}
+	} else {
+		for _, name := range defaultFiles {
+			if _, err := os.Stat(name); err == nil {
+				if err := cfg.loadFile(name, false); err != nil {
+					return nil, err
+				}
+				break
+			}
+		}
+	}
+
+	if err := cfg.loadEnv(); err != nil {
+		return nil, err
+	}
+	if err := cfg.Validate(scope); err != nil {
+		return nil, err
+	}
+	return &cfg, nil
+}
+
+func (cfg *Config) loadFile(path string, required bool) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) && !required {
+			return nil
+		}
+		return fmt.Errorf("config: reading %s: %w", path, err)
+	}
+	if strings.EqualFold(filepath.Ext(path), ".toml") {
+		// TOML shares the YAML keys and value formats, such as "30s" durations
+		var values map[string]interface{}
+		if err := toml.Unmarshal(data, &values); err != nil {
+			return fmt.Errorf("config: parsing %s: %w", path, err)
+		}
+		if data, err = yaml.Marshal(values); err != nil {
+			return fmt.Errorf("config: parsing %s: %w", path, err)
+		}
+	}
+	if err := yaml.Unmarshal(data, cfg); err != nil {
+		return fmt.Errorf("config: parsing %s: %w", path, err)
+	}
+	return nil
+}
+
+// loadEnv applies environment variable overrides
+func (cfg *Config) loadEnv() error {
+	var errs []error
+
+	setString(&cfg.Server.Port, "PORT")
+	setString(&cfg.Server.PublicURL, "PUBLIC_URL")
+	setDuration(&cfg.Server.ShutdownTimeout, "SHUTDOWN_TIMEOUT", &errs)
+	setDuration(&cfg.Server.DrainDelay, "SHUTDOWN_DRAIN_DELAY", &errs)
+	setList(&cfg.Server.CORSOrigins, "CORS_ORIGINS")
+
+	setString(&cfg.Database.URL, "POSTGRES_URL")
+	setList(&cfg.Database.ReplicaURLs, "POSTGRES_REPLICA_URLS")
+	setInt(&cfg.Database.MaxOpenConns, "DB_MAX_OPEN_CONNS", &errs)
+	setInt(&cfg.Database.MaxIdleConns, "DB_MAX_IDLE_CONNS", &errs)
+	setDuration(&cfg.Database.ConnMaxLifetime, "DB_CONN_MAX_LIFETIME", &errs)
+	setDuration(&cfg.Database.ConnMaxIdleTime, "DB_CONN_MAX_IDLE_TIME", &errs)
+	setInt(&cfg.Database.ConnectRetries, "DB_CONNECT_RETRIES", &errs)
+	setDuration(&cfg.Database.ConnectBackoff, "DB_CONNECT_BACKOFF", &errs)
+	setDuration(&cfg.Database.StatementTimeout, "DB_STATEMENT_TIMEOUT", &errs)
+
+	setString(&cfg.Auth.UserSecret, "JWT_SECRET")
+	setString(&cfg.Auth.CompanySecret, "JWT_COMPANY_SECRET")
+	setDuration(&cfg.Auth.UserTokenTTL, "USER_TOKEN_TTL", &errs)
+	setDuration(&cfg.Auth.CompanyTokenTTL, "COMPANY_TOKEN_TTL", &errs)
+	setString(&cfg.Auth.CookieDomain, "COOKIE_DOMAIN")
+	setBool(&cfg.Auth.CookieSecure, "COOKIE_SECURE", &errs)
+	setString(&cfg.Auth.CookieSameSite, "COOKIE_SAMESITE")
+
+	setString(&cfg.Admin.APIKey, "ADMIN_API_KEY")
+
+	setDuration(&cfg.Workers.JobExpiryInterval, "JOB_EXPIRY_INTERVAL", &errs)
+	setDuration(&cfg.Workers.JobAlertInterval, "JOB_ALERT_INTERVAL", &errs)
+
+	setString(&cfg.Mail.SMTPHost, "SMTP_HOST")
+	setInt(&cfg.Mail.SMTPPort, "SMTP_PORT", &errs)
+	setString(&cfg.Mail.Username, "SMTP_USERNAME")
+	setString(&cfg.Mail.Password, "SMTP_PASSWORD")
+	setString(&cfg.Mail.From, "MAIL_FROM")
+
+	setString(&cfg.Geo.GazetteerFile, "GAZETTEER_FILE")
+
+	setString(&cfg.Storage.Backend, "STORAGE_BACKEND")
+	setString(&cfg.Storage.LocalDir, "STORAGE_LOCAL_DIR")
+	setString(&cfg.Storage.S3.Endpoint, "S3_ENDPOINT")
+	setString(&cfg.Storage.S3.Region, "S3_REGION")
+	setString(&cfg.Storage.S3.Bucket, "S3_BUCKET")
+	setString(&cfg.Storage.S3.AccessKey, "S3_ACCESS_KEY_ID")
+	setString(&cfg.Storage.S3.SecretKey, "S3_SECRET_ACCESS_KEY")
+	setBool(&cfg.Storage.S3.PathStyle, "S3_PATH_STYLE", &errs)
+	setString(&cfg.Storage.SigningKey, "STORAGE_SIGNING_KEY")
+	setDuration(&cfg.Storage.DownloadURLTTL, "DOWNLOAD_URL_TTL", &errs)
+	setInt(&cfg.Storage.MaxUploadSize, "MAX_UPLOAD_SIZE", &errs)
+
+	return errors.Join(errs...)
+}
+
+// Validate reports every invalid or missing setting scope needs at once
+func (cfg *Config) Validate(scope Scope) error {
+	var errs []error
+
+	if cfg.Database.URL == "" {
+		errs = append(errs, errors.New("database.url (POSTGRES_URL) is required"))
+	}
+	if cfg.Database.MaxOpenConns < 0 || cfg.Database.MaxIdleConns < 0 {
+		errs = append(errs, errors.New("database.max_open_conns and database.max_idle_conns must not be negative"))
+	}
+	if cfg.Database.MaxOpenConns > 0 && cfg.Database.MaxIdleConns > cfg.Database.MaxOpenConns {
+		errs = append(errs, errors.New("database.max_idle_conns cannot exceed database.max_open_conns"))
+	}
+	if cfg.Database.ConnectRetries < 1 {
+		errs = append(errs, errors.New("database.connect_retries (DB_CONNECT_RETRIES) must be at least 1"))
+	}
+	if cfg.Database.ConnectBackoff <= 0 {
+		errs = append(errs, errors.New("database.connect_backoff (DB_CONNECT_BACKOFF) must be positive"))
+	}
+	if cfg.Database.StatementTimeout < 0 {
+		errs = append(errs, errors.New("database.statement_timeout (DB_STATEMENT_TIMEOUT) must not be negative"))
+	}
+	if scope == ScopeDatabase {
+		return joinInvalid(errs)
+	}
+
+	if port, err := strconv.Atoi(cfg.Server.Port); err != nil || port < 1 || port > 65535 {
+		errs = append(errs, fmt.Errorf("server.port (PORT) must be a number between 1 and 65535, got %q", cfg.Server.Port))
+	}
+	if cfg.Server.ShutdownTimeout <= 0 {
+		errs = append(errs, errors.New("server.shutdown_timeout (SHUTDOWN_TIMEOUT) must be positive"))
+	}
+	if cfg.Server.DrainDelay < 0 {
+		errs = append(errs, errors.New("server.drain_delay (SHUTDOWN_DRAIN_DELAY) must not be negative"))
+	}
+	if cfg.Auth.UserSecret == "" {
+		errs = append(errs, errors.New("auth.user_secret (JWT_SECRET) is required"))
+	}
+	if cfg.Auth.CompanySecret == "" {
+		errs = append(errs, errors.New("auth.company_secret (JWT_COMPANY_SECRET) is required"))
+	}
+	if cfg.Auth.UserTokenTTL <= 0 {
+		errs = append(errs, errors.New("auth.user_token_ttl (USER_TOKEN_TTL) must be positive"))
+	}
+	if cfg.Auth.CompanyTokenTTL <= 0 {
+		errs = append(errs, errors.New("auth.company_token_ttl (COMPANY_TOKEN_TTL) must be positive"))
+	}
+	if cfg.Workers.JobExpiryInterval <= 0 {
+		errs = append(errs, errors.New("workers.job_expiry_interval (JOB_EXPIRY_INTERVAL) must be positive"))
+	}
+	if cfg.Workers.JobAlertInterval <= 0 {
+		errs = append(errs, errors.New("workers.job_alert_interval (JOB_ALERT_INTERVAL) must be positive"))
+	}
+	if u, err := url.Parse(cfg.Server.PublicURL); err != nil || u.Scheme == "" || u.Host == "" {
+		errs = append(errs, fmt.Errorf("server.public_url (PUBLIC_URL) must be an absolute URL, got %q", cfg.Server.PublicURL))
+	}
+	if cfg.Mail.SMTPHost != "" && cfg.Mail.From == "" {
+		errs = append(errs, errors.New("mail.from (MAIL_FROM) is required when SMTP is configured"))
+	}
+	switch cfg.Storage.Backend {
+	case "local":
+		if cfg.Storage.LocalDir == "" {
+			errs = append(errs, errors.New("storage.local_dir (STORAGE_LOCAL_DIR) is required for the local backend"))
+		}
+	case "s3":
+		if cfg.Storage.S3.Bucket == "" || cfg.Storage.S3.AccessKey == "" || cfg.Storage.S3.SecretKey == "" {
+			errs = append(errs, errors.New("storage.s3 bucket, access_key and secret_key (S3_BUCKET, S3_ACCESS_KEY_ID, S3_SECRET_ACCESS_KEY) are required for the s3 backend"))
+		}
+		if cfg.Storage.S3.Endpoint != "" {
+			if u, err := url.Parse(cfg.Storage.S3.Endpoint); err != nil || u.Scheme == "" || u.Host == "" {
+				errs = append(errs, fmt.Errorf("storage.s3.endpoint (S3_ENDPOINT) must be an absolute URL, got %q", cfg.Storage.S3.Endpoint))
+			}
+		}
+	default:
+		errs = append(errs, fmt.Errorf("storage.backend (STORAGE_BACKEND) must be local or s3, got %q", cfg.Storage.Backend))
+	}
+	if cfg.Storage.DownloadURLTTL <= 0 {
+		errs = append(errs, errors.New("storage.download_url_ttl (DOWNLOAD_URL_TTL) must be positive"))
+	}
+	if cfg.Storage.MaxUploadSize <= 0 {
+		errs = append(errs, errors.New("storage.max_upload_size (MAX_UPLOAD_SIZE) must be positive"))
+	}
+	switch strings.ToLower(cfg.Auth.CookieSameSite) {
+	case "lax", "strict":
+	case "none":
+		if !cfg.Auth.CookieSecure {
+			errs = append(errs, errors.New("auth.cookie_same_site none requires auth.cookie_secure (COOKIE_SECURE)"))
+		}
+	default:
+		errs = append(errs, fmt.Errorf("auth.cookie_same_site (COOKIE_SAMESITE) must be lax, strict or none, got %q", cfg.Auth.CookieSameSite))
+	}
+
+	return joinInvalid(errs)
+}
+
+func joinInvalid(errs []error) error {
+	if len(errs) > 0 {
+		return fmt.Errorf("invalid configuration:\n%w", errors.Join(errs...))
+	}
+	return nil
+}
+
+// SameSite returns the cookie SameSite mode
+func (a AuthConfig) SameSite() http.SameSite {
+	switch strings.ToLower(a.CookieSameSite) {
+	case "strict":
+		return http.SameSiteStrictMode
+	case "none":
+		return http.SameSiteNoneMode
+	default:
+		return http.SameSiteLaxMode
+	}
+}
+
+func setString(dst *string, key string) {
+	if value, ok := os.LookupEnv(key); ok && value != "" {
+		*dst = value
+	}
+}
+
+func setList(dst *[]string, key string) {
+	value, ok := os.LookupEnv(key)
+	if !ok || value == "" {
+		return
+	}
+	var list []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			list = append(list, item)
+		}
+	}
+	*dst = list
+}
+
+func setDuration(dst *time.Duration, key string, errs *[]error) {
+	value, ok := os.LookupEnv(key)
+	if !ok || value == "" {
+		return
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil {
+		*errs = append(*errs, fmt.Errorf("%s: invalid duration %q", key, value))
+		return
+	}
+	*dst = d
+}
+
+func setInt(dst *int, key string, errs *[]error) {
+	value, ok := os.LookupEnv(key)
+	if !ok || value == "" {
+		return
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil {
+		*errs = append(*errs, fmt.Errorf("%s: invalid integer %q", key, value))
+		return
+	}
+	*dst = n
+}
+
+func setBool(dst *bool, key string, errs *[]error) {
+	value, ok := os.LookupEnv(key)
+	if !ok || value == "" {
+		return
+	}
+	b, err := strconv.ParseBool(value)
+	if err != nil {
+		*errs = append(*errs, fmt.Errorf("%s: invalid boolean %q", key, value))
+		return
+	}
+	*dst = b
+}
@@ -1,12 +1,21 @@
 package controller
 
 import (
+	"encoding/json"
+	"errors"
+	"fmt"
 	"net/http"
+	"sort"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/sahilq312/workly/helpers"
 	"github.com/sahilq312/workly/initializer"
+	"github.com/sahilq312/workly/matching"
 	"github.com/sahilq312/workly/model"
+	"gorm.io/gorm"
 )
 
 func ApplyForJob(c *gin.Context) {
@@ -26,11 +35,8 @@ func ApplyForJob(c *gin.Context) {
 		return
 	}
 
-	var body struct {
-		JobID uint `json:"job_id"`
-	}
-	if err := c.ShouldBindJSON(&body); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+	body, ok := readApplicationRequest(c)
+	if !ok {
 		return
 	}
 	if body.JobID == 0 {
@@ -38,15 +44,196 @@ func ApplyForJob(c *gin.Context) {
 		return
 	}
 
+	// Only published jobs that have not expired accept applications
+	var job model.Job
+	if err := initializer.DB.Preload("Company").Preload("Questions").Preload("Questions.Options").Preload("Stages", orderByPosition).First(&job, body.JobID).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Job not found"})
+		return
+	}
+	if !job.IsOpen(time.Now()) {
+		c.JSON(http.StatusConflict, gin.H{"error": "This job is not accepting applications"})
+		return
+	}
+
+	if !checkReapply(c, userID, job) {
+		return
+	}
+
+	answers, knockedOut, err := helpers.EvaluateAnswers(job.Questions, body.Answers)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	documents, err := helpers.StoreUploads(c.Request.Context(), initializer.Storage,
+		"applications/"+strconv.FormatUint(uint64(userID), 10), body.Uploads)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to store documents"})
+		return
+	}
+
+	// New applications enter the first applied stage of the job's pipeline,
+	// and a knockout answer rejects them straight away
+	entry := helpers.FirstStage(job.Stages, model.ApplicationApplied)
 	application := model.Application{
-		UserID: userID,
-		JobID:  body.JobID,
+		UserID:     userID,
+		JobID:      body.JobID,
+		Status:     model.ApplicationApplied,
+		StageID:    helpers.StageID(entry),
+		KnockedOut: knockedOut,
+		Answers:    answers,
+		Documents:  documents,
+		History: []model.ApplicationStatusChange{
+			{Event: model.HistoryStatusChange, ToStatus: model.ApplicationApplied, ToStage: helpers.StageName(entry), ActorType: model.ActorUser, ActorID: userID},
+		},
+	}
+	if knockedOut {
+		rejected := helpers.FirstStage(job.Stages, model.ApplicationRejected)
+		application.Status = model.ApplicationRejected
+		application.StageID = helpers.StageID(rejected)
+		application.History = append(application.History, model.ApplicationStatusChange{
+			Event:      model.HistoryStatusChange,
+			FromStatus: model.ApplicationApplied,
+			ToStatus:   model.ApplicationRejected,
+			FromStage:  helpers.StageName(entry),
+			ToStage:    helpers.StageName(rejected),
+			ActorType:  model.ActorSystem,
+			Note:       "Rejected by a screening question",
+		})
 	}
 	if result := initializer.DB.Create(&application); result.Error != nil {
+		helpers.DeleteDocuments(c.Request.Context(), initializer.Storage, documents)
+		// Lost a race with another submission of the same application
+		if errors.Is(result.Error, gorm.ErrDuplicatedKey) {
+			c.JSON(http.StatusConflict, gin.H{"error": "You have already applied to this job"})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to submit application"})
 		return
 	}
-	c.JSON(http.StatusOK, gin.H{"message": "Application submitted successfully"})
+	if knockedOut {
+		helpers.RecordAudit(nil, helpers.AuditEntry{
+			Action:     model.AuditApplicationStatus,
+			EntityType: "application",
+			EntityID:   application.ID,
+			Before:     gin.H{"status": model.ApplicationApplied},
+			After:      gin.H{"status": model.ApplicationRejected, "reason": "knockout"},
+		})
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Application submitted successfully", "application_id": application.ID})
+}
+
+// checkReapply allows one active application per user and job, and makes a
+// rejected candidate wait out the company's cool-down before applying again.
+// Withdrawn applications do not block a new one. It writes the error
+// response itself and reports false when the user may not apply.
+func checkReapply(c *gin.Context, userID uint, job model.Job) bool {
+	var previous model.Application
+	err := initializer.DB.Where("user_id = ? AND job_id = ?", userID, job.ID).Order("applied_at DESC, id DESC").First(&previous).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return true
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to check previous applications"})
+		return false
+	}
+	switch previous.Status {
+	case model.ApplicationWithdrawn:
+		return true
+	case model.ApplicationRejected:
+	default:
+		c.JSON(http.StatusConflict, gin.H{"error": "You have already applied to this job", "application_id": previous.ID})
+		return false
+	}
+
+	cooldown := job.Company.ReapplyCooldownDays
+	if cooldown < 0 {
+		c.JSON(http.StatusConflict, gin.H{"error": "This company does not accept new applications after a rejection", "application_id": previous.ID})
+		return false
+	}
+	rejectedAt := previous.UpdatedAt
+	var rejection model.ApplicationStatusChange
+	if err := initializer.DB.
+		Where("application_id = ? AND to_status = ?", previous.ID, model.ApplicationRejected).
+		Order("created_at DESC").
+		First(&rejection).Error; err == nil {
+		rejectedAt = rejection.CreatedAt
+	}
+	reapplyAfter := rejectedAt.AddDate(0, 0, cooldown)
+	if time.Now().Before(reapplyAfter) {
+		c.JSON(http.StatusConflict, gin.H{
+			"error":          "You can apply to this job again after " + reapplyAfter.Format("2 January 2006"),
+			"application_id": previous.ID,
+			"reapply_after":  reapplyAfter,
+		})
+		return false
+	}
+	return true
+}
+
+// applicationRequest is the body of an application: JSON, or a multipart form
+// carrying the same fields plus resume and cover_letter files
+type applicationRequest struct {
+	JobID   uint                  `json:"job_id"`
+	Answers []helpers.AnswerInput `json:"answers"` // Screening question answers
+	Uploads []helpers.Upload      `json:"-"`
+}
+
+// readApplicationRequest parses an application body. It writes the error
+// response itself and reports false on failure.
+func readApplicationRequest(c *gin.Context) (applicationRequest, bool) {
+	var body applicationRequest
+	if c.ContentType() != "multipart/form-data" {
+		if err := c.ShouldBindJSON(&body); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+			return body, false
+		}
+		return body, true
+	}
+
+	// Room for both documents plus the other form fields
+	maxSize := initializer.Config.Storage.MaxUploadSize
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, int64(2*maxSize+1<<20))
+	if err := c.Request.ParseMultipartForm(int64(maxSize)); err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": "Upload is too large"})
+			return body, false
+		}
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid multipart form"})
+		return body, false
+	}
+	if jobID := c.PostForm("job_id"); jobID != "" {
+		id, err := strconv.ParseUint(jobID, 10, 32)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid job ID"})
+			return body, false
+		}
+		body.JobID = uint(id)
+	}
+	if answers := c.PostForm("answers"); answers != "" {
+		if err := json.Unmarshal([]byte(answers), &body.Answers); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "answers must be a JSON list"})
+			return body, false
+		}
+	}
+	for _, kind := range []string{model.DocumentResume, model.DocumentCoverLetter} {
+		header, err := c.FormFile(kind)
+		if errors.Is(err, http.ErrMissingFile) {
+			continue
+		}
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid " + kind + " upload"})
+			return body, false
+		}
+		upload, err := helpers.ReadUpload(header, kind, maxSize)
+		if err != nil {
+			c.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+			return body, false
+		}
+		body.Uploads = append(body.Uploads, upload)
+	}
+	return body, true
 }
 
 func GetUserApplications(c *gin.Context) {
@@ -90,45 +277,13 @@ func DeleteApplicationByCompany(c *gin.Context) {
 		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid company ID"})
 		return
 	}
-	jobID, err := strconv.Atoi(c.Param("id"))
-	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid job ID"})
-		return
-	}
-	result := initializer.DB.Where("company_id = ?", companyID).Delete(&model.Application{}, jobID)
-	if result.Error != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete application"})
-		return
-	}
-	if result.RowsAffected == 0 {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Application not found"})
-		return
-	}
-	c.JSON(http.StatusOK, gin.H{"message": "Application deleted successfully"})
-}
-
-func DeleteApplication(c *gin.Context) {
-	user, ok := c.Get("user")
-	if !ok || user == nil {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not found"})
-		return
-	}
-	userModel, ok := user.(model.User)
-	if !ok {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid user type assertion"})
-		return
-	}
-	userID := userModel.ID
-	if userID == 0 {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid user ID"})
-		return
-	}
 	applicationID, err := strconv.Atoi(c.Param("id"))
 	if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid application ID"})
 		return
 	}
-	result := initializer.DB.Where("user_id = ?", userID).Delete(&model.Application{}, applicationID)
+	companyJobs := initializer.DB.Model(&model.Job{}).Select("id").Where("company_id = ?", companyID)
+	result := initializer.DB.Where("job_id IN (?)", companyJobs).Delete(&model.Application{}, applicationID)
 	if result.Error != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete application"})
 		return
@@ -162,13 +317,26 @@ func GetApplicationByID(c *gin.Context) {
 		return
 	}
 	var application model.Application
-	if result := initializer.DB.Where("user_id = ?", userID).First(&application, applicationID); result.Error != nil {
+	if result := initializer.DB.Preload("Answers").Preload("Documents").Where("user_id = ?", userID).First(&application, applicationID); result.Error != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch application"})
 		return
 	}
+	helpers.SignDocumentURLs(application.Documents, model.ActorUser, userID)
 	c.JSON(http.StatusOK, gin.H{"application": application})
 }
 
+// ScoredApplication is an application annotated with how well the applicant fits the job
+type ScoredApplication struct {
+	model.Application
+	Match matching.CandidateMatch `json:"match"`
+}
+
+// GetApplicationsByCompany lists applications to the company's jobs ranked by
+// candidate match, with their screening answers and document download
+// links. Optional query parameters:
+// job_id, status, stage_id, knocked_out, min_score, page and sort (score,
+// score_asc, newest or oldest). Score sorting and min_score need the filters
+// to match at most maxRankedApplications; longer lists default to newest.
 func GetApplicationsByCompany(c *gin.Context) {
 	company, ok := c.Get("company")
 	if !ok || company == nil {
@@ -185,14 +353,178 @@ func GetApplicationsByCompany(c *gin.Context) {
 		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid company ID"})
 		return
 	}
-	var applications []model.Application
-	if result := initializer.DB.Where("company_id = ?", companyID).Find(&applications); result.Error != nil {
+	query := initializer.Reader(c.Request.Context()).
+		Joins("JOIN jobs ON jobs.id = applications.job_id").
+		Where("jobs.company_id = ?", companyID)
+	if jobID := c.Query("job_id"); jobID != "" {
+		id, err := strconv.ParseUint(jobID, 10, 32)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid job ID"})
+			return
+		}
+		query = query.Where("applications.job_id = ?", id)
+	}
+	if status := c.Query("status"); status != "" {
+		if !helpers.ContainsString(model.ApplicationStatuses, status) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "status must be one of " + strings.Join(model.ApplicationStatuses, ", ")})
+			return
+		}
+		query = query.Where("applications.status = ?", status)
+	}
+	if stageID := c.Query("stage_id"); stageID != "" {
+		id, err := strconv.ParseUint(stageID, 10, 32)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid stage ID"})
+			return
+		}
+		query = query.Where("applications.stage_id = ?", id)
+	}
+	if knockedOut := c.Query("knocked_out"); knockedOut != "" {
+		k, err := strconv.ParseBool(knockedOut)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "knocked_out must be true or false"})
+			return
+		}
+		query = query.Where("applications.knocked_out = ?", k)
+	}
+	var minScore float64
+	if minStr := c.Query("min_score"); minStr != "" {
+		m, err := strconv.ParseFloat(minStr, 64)
+		if err != nil || m < 0 || m > 100 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "min_score must be between 0 and 100"})
+			return
+		}
+		minScore = m
+	}
+	sortBy := c.DefaultQuery("sort", "score")
+	if !helpers.ContainsString([]string{"score", "score_asc", "newest", "oldest"}, sortBy) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "sort must be score, score_asc, newest or oldest"})
+		return
+	}
+	page := 1
+	perPage := 20
+	if pageStr := c.Query("page"); pageStr != "" {
+		if p, err := strconv.Atoi(pageStr); err == nil && p > 0 {
+			page = p
+		}
+	}
+
+	var totalRows int64
+	if err := query.Session(&gorm.Session{}).Model(&model.Application{}).Count(&totalRows).Error; err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch applications"})
 		return
 	}
-	c.JSON(http.StatusOK, gin.H{"applications": applications})
+	order := "applications.applied_at DESC, applications.id DESC"
+	if sortBy == "oldest" {
+		order = "applications.applied_at, applications.id"
+	}
+	offset := (page - 1) * perPage
+	now := time.Now()
+	var scored []ScoredApplication
+
+	// Lists too long to rank default to the newest applications first
+	if c.Query("sort") == "" && minScore == 0 && totalRows > maxRankedApplications {
+		sortBy = "newest"
+	}
+	if (sortBy == "newest" || sortBy == "oldest") && minScore == 0 {
+		// Date orders need no scores to paginate, so only the page is loaded
+		var applications []model.Application
+		if err := scoringPreloads(query).Order(order).Offset(offset).Limit(perPage).Find(&applications).Error; err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch applications"})
+			return
+		}
+		for _, application := range applications {
+			scored = append(scored, ScoredApplication{Application: application, Match: matching.ScoreCandidate(application.User, application.Job, now)})
+		}
+	} else {
+		// Scores depend on the applicant's whole profile, so rank in memory,
+		// without the details that are loaded for the returned page below.
+		// Ranking only part of a large list would be wrong, so it is refused.
+		if totalRows > maxRankedApplications {
+			c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf(
+				"Sorting or filtering by score covers at most %d applications; narrow the list with job_id, status, stage_id or knocked_out", maxRankedApplications)})
+			return
+		}
+		var applications []model.Application
+		if err := scoringPreloads(query).Find(&applications).Error; err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch applications"})
+			return
+		}
+		for _, application := range applications {
+			match := matching.ScoreCandidate(application.User, application.Job, now)
+			if match.Score < minScore {
+				continue
+			}
+			scored = append(scored, ScoredApplication{Application: application, Match: match})
+		}
+		sort.SliceStable(scored, func(i, j int) bool {
+			a, b := scored[i], scored[j]
+			switch sortBy {
+			case "score_asc":
+				return a.Match.Score < b.Match.Score
+			case "newest":
+				return a.AppliedAt.After(b.AppliedAt)
+			case "oldest":
+				return a.AppliedAt.Before(b.AppliedAt)
+			}
+			return a.Match.Score > b.Match.Score
+		})
+		totalRows = int64(len(scored))
+		if offset > len(scored) {
+			offset = len(scored)
+		}
+		scored = scored[offset:min(offset+perPage, len(scored))]
+	}
+
+	// Stages, answers and documents for the returned page only
+	if len(scored) > 0 {
+		ids := make([]uint, len(scored))
+		for i := range scored {
+			ids[i] = scored[i].ID
+		}
+		var details []model.Application
+		if err := initializer.Reader(c.Request.Context()).
+			Preload("Stage").Preload("Answers").Preload("Documents").
+			Where("id IN ?", ids).Find(&details).Error; err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch applications"})
+			return
+		}
+		byID := make(map[uint]model.Application, len(details))
+		for _, detail := range details {
+			byID[detail.ID] = detail
+		}
+		for i := range scored {
+			detail := byID[scored[i].ID]
+			scored[i].Stage, scored[i].Answers, scored[i].Documents = detail.Stage, detail.Answers, detail.Documents
+			helpers.SignDocumentURLs(scored[i].Documents, model.ActorCompany, companyID)
+		}
+	}
+	if scored == nil {
+		scored = []ScoredApplication{}
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"applications": scored,
+		"page":         page,
+		"totalRows":    totalRows,
+		"totalPages":   (totalRows + int64(perPage) - 1) / int64(perPage),
+		"sort":         sortBy,
+	})
+}
+
+// Most applications that can be ranked or filtered by score in one request
+const maxRankedApplications = 500
+
+// scoringPreloads loads the applicant profile and job skills candidate
+// scores are computed from
+func scoringPreloads(query *gorm.DB) *gorm.DB {
+	return query.Preload("User.Skills").Preload("User.Experience.Skills").Preload("User.Education").Preload("Job.Skills")
 }
 
+// UpdateApplicationStatusByCompany moves an application to one of the
+// company's jobs to a new state, with an optional note for the history.
+// Only the transitions allowed by model.CanTransitionApplication are
+// accepted, and withdrawing is left to the candidate.
 func UpdateApplicationStatusByCompany(c *gin.Context) {
 	id, err := strconv.Atoi(c.Param("id"))
 	if err != nil {
@@ -201,6 +533,7 @@ func UpdateApplicationStatusByCompany(c *gin.Context) {
 	}
 	var body struct {
 		Status string `json:"status"`
+		Note   string `json:"note"`
 	}
 	if err := c.ShouldBindJSON(&body); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
@@ -210,6 +543,14 @@ func UpdateApplicationStatusByCompany(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Status is required"})
 		return
 	}
+	if !helpers.ContainsString(model.ApplicationStatuses, body.Status) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "status must be one of " + strings.Join(model.ApplicationStatuses, ", ")})
+		return
+	}
+	if body.Status == model.ApplicationWithdrawn {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Only the candidate can withdraw an application"})
+		return
+	}
 	company, ok := c.Get("company")
 	if !ok || company == nil {
 		c.JSON(http.StatusUnauthorized, gin.H{"error": "Company not found"})
@@ -226,15 +567,157 @@ func UpdateApplicationStatusByCompany(c *gin.Context) {
 		return
 	}
 	// Check if the application exists and belongs to the company before updating
+	application, ok := companyApplication(c, companyID, id)
+	if !ok {
+		return
+	}
+	if body.Status == application.Status {
+		c.JSON(http.StatusConflict, gin.H{"error": "Application is already " + body.Status})
+		return
+	}
+	stage, err := helpers.StageForStatus(initializer.DB, application, body.Status)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update application status"})
+		return
+	}
+	moveApplication(c, companyID, application, body.Status, stage, body.Note)
+}
+
+// moveApplication applies a company's change of state and stage to one of
+// its applications and writes the response
+func moveApplication(c *gin.Context, companyID uint, application model.Application, status string, stage *model.PipelineStage, note string) {
+	note, err := helpers.ValidateStatusChange(application, status, note)
+	if err != nil {
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		return
+	}
+	previous := gin.H{"status": application.Status, "stage_id": application.StageID}
+	change, err := helpers.ChangeApplicationStatus(initializer.DB, &application, helpers.StatusMove{
+		Status:    status,
+		Stage:     stage,
+		ActorType: model.ActorCompany,
+		ActorID:   companyID,
+		Note:      note,
+	})
+	if errors.Is(err, helpers.ErrStatusConflict) {
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update application status"})
+		return
+	}
+
+	helpers.RecordAudit(c, helpers.AuditEntry{
+		Action:     model.AuditApplicationStatus,
+		EntityType: "application",
+		EntityID:   application.ID,
+		Before:     previous,
+		After:      gin.H{"status": application.Status, "stage_id": application.StageID},
+	})
+	c.JSON(http.StatusOK, gin.H{"message": "Application status updated successfully", "change": change})
+}
+
+// companyApplication loads an application to one of the company's jobs. It
+// writes the error response itself and reports false when there is none.
+func companyApplication(c *gin.Context, companyID uint, id int) (model.Application, bool) {
 	var application model.Application
-	if result := initializer.DB.Where("company_id = ?", companyID).Where("id = ?", id).First(&application); result.Error != nil {
+	if result := initializer.DB.
+		Preload("Stage").
+		Joins("JOIN jobs ON jobs.id = applications.job_id").
+		Where("jobs.company_id = ?", companyID).
+		Where("applications.id = ?", id).
+		First(&application); result.Error != nil {
 		c.JSON(http.StatusNotFound, gin.H{"error": "Application not found or does not belong to this company"})
+		return application, false
+	}
+	return application, true
+}
+
+// GetApplicationTimeline returns the status history of one of the user's
+// applications. Notes written by the company are internal and left out.
+func GetApplicationTimeline(c *gin.Context) {
+	user, ok := c.Get("user")
+	if !ok || user == nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not found"})
 		return
 	}
-	result := initializer.DB.Model(&model.Application{}).Where("company_id = ?", companyID).Where("id = ?", id).Update("status", body.Status)
-	if result.Error != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update application status"})
+	userModel, ok := user.(model.User)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid user type assertion"})
+		return
+	}
+	userID := userModel.ID
+	if userID == 0 {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid user ID"})
 		return
 	}
-	c.JSON(http.StatusOK, gin.H{"message": "Application status updated successfully"})
+	applicationID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid application ID"})
+		return
+	}
+	var application model.Application
+	if result := initializer.DB.Where("user_id = ?", userID).First(&application, applicationID); result.Error != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Application not found"})
+		return
+	}
+	timeline, ok := applicationTimeline(c, application.ID)
+	if !ok {
+		return
+	}
+	for i := range timeline {
+		if timeline[i].ActorType != model.ActorUser {
+			timeline[i].Note = ""
+			timeline[i].ActorID = 0
+		}
+	}
+	c.JSON(http.StatusOK, gin.H{"status": application.Status, "timeline": timeline})
+}
+
+// GetApplicationTimelineByCompany returns the full status history of an
+// application to one of the company's jobs
+func GetApplicationTimelineByCompany(c *gin.Context) {
+	company, ok := c.Get("company")
+	if !ok || company == nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Company not found"})
+		return
+	}
+	companyModel, ok := company.(model.Company)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid company type assertion"})
+		return
+	}
+	companyID := companyModel.ID
+	if companyID == 0 {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid company ID"})
+		return
+	}
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid application ID"})
+		return
+	}
+	application, ok := companyApplication(c, companyID, id)
+	if !ok {
+		return
+	}
+	timeline, ok := applicationTimeline(c, application.ID)
+	if !ok {
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"status": application.Status, "timeline": timeline})
+}
+
+// applicationTimeline loads the history of an application, oldest first
+func applicationTimeline(c *gin.Context, applicationID uint) ([]model.ApplicationStatusChange, bool) {
+	timeline := []model.ApplicationStatusChange{}
+	if err := initializer.Reader(c.Request.Context()).
+		Where("application_id = ?", applicationID).
+		Order("created_at, id").
+		Find(&timeline).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch application history"})
+		return nil, false
+	}
+	return timeline, true
 }
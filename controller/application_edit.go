@@ -0,0 +1,273 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sahilq312/workly/helpers"
+	"github.com/sahilq312/workly/initializer"
+	"github.com/sahilq312/workly/model"
+	"gorm.io/gorm"
+)
+
+// WithdrawApplication takes one of the user's applications out of the
+// running with an optional reason. The application and its history stay
+// visible to the company.
+func WithdrawApplication(c *gin.Context) {
+	userID, application, ok := userApplication(c)
+	if !ok {
+		return
+	}
+	var body struct {
+		Reason string `json:"reason"`
+	}
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&body); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+			return
+		}
+	}
+	note, err := helpers.ValidateStatusChange(application, model.ApplicationWithdrawn, body.Reason)
+	if err != nil {
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		return
+	}
+	stage, err := helpers.StageForStatus(initializer.DB, application, model.ApplicationWithdrawn)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to withdraw application"})
+		return
+	}
+	previousStatus := application.Status
+	change, err := helpers.ChangeApplicationStatus(initializer.DB, &application, helpers.StatusMove{
+		Status:    model.ApplicationWithdrawn,
+		Stage:     stage,
+		ActorType: model.ActorUser,
+		ActorID:   userID,
+		Note:      note,
+	})
+	if errors.Is(err, helpers.ErrStatusConflict) {
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to withdraw application"})
+		return
+	}
+
+	helpers.RecordAudit(c, helpers.AuditEntry{
+		Action:     model.AuditApplicationStatus,
+		EntityType: "application",
+		EntityID:   application.ID,
+		Before:     gin.H{"status": previousStatus},
+		After:      gin.H{"status": model.ApplicationWithdrawn, "reason": note},
+	})
+	c.JSON(http.StatusOK, gin.H{"message": "Application withdrawn successfully", "change": change})
+}
+
+// UpdateApplication lets a candidate replace their documents and screening
+// answers until the company starts reviewing the application, that is while
+// it is still applied and in the first stage of the job's pipeline. It takes
+// the same JSON or multipart body as ApplyForJob; uploaded files replace the
+// document of the same kind, and answers replace all previous answers.
+func UpdateApplication(c *gin.Context) {
+	userID, application, ok := userApplication(c)
+	if !ok {
+		return
+	}
+	body, ok := readApplicationRequest(c)
+	if !ok {
+		return
+	}
+	if body.Answers == nil && len(body.Uploads) == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Send answers or documents to update"})
+		return
+	}
+
+	var job model.Job
+	if err := initializer.DB.
+		Preload("Questions").Preload("Questions.Options").Preload("Stages", orderByPosition).
+		First(&job, application.JobID).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Job not found"})
+		return
+	}
+	entry := helpers.FirstStage(job.Stages, model.ApplicationApplied)
+	if application.Status != model.ApplicationApplied ||
+		entry != nil && application.StageID != nil && *application.StageID != entry.ID {
+		c.JSON(http.StatusConflict, gin.H{"error": "Applications can only be edited until the company starts reviewing them"})
+		return
+	}
+
+	var answers []model.ScreeningAnswer
+	knockedOut := false
+	if body.Answers != nil {
+		var err error
+		if answers, knockedOut, err = helpers.EvaluateAnswers(job.Questions, body.Answers); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+
+	ctx := c.Request.Context()
+	documents, err := helpers.StoreUploads(ctx, initializer.Storage,
+		"applications/"+strconv.FormatUint(uint64(userID), 10), body.Uploads)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to store documents"})
+		return
+	}
+
+	var replaced []model.Document
+	err = initializer.DB.Transaction(func(tx *gorm.DB) error {
+		// Claim the application so a concurrent review or withdrawal wins cleanly
+		current := tx.Model(&model.Application{}).Where("id = ? AND status = ?", application.ID, model.ApplicationApplied)
+		if application.StageID == nil {
+			current = current.Where("stage_id IS NULL")
+		} else {
+			current = current.Where("stage_id = ?", *application.StageID)
+		}
+		updates := map[string]interface{}{"updated_at": time.Now()}
+		if body.Answers != nil {
+			updates["knocked_out"] = knockedOut
+		}
+		result := current.Updates(updates)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return helpers.ErrStatusConflict
+		}
+
+		var changes []string
+		if body.Answers != nil {
+			if err := tx.Where("application_id = ?", application.ID).Delete(&model.ScreeningAnswer{}).Error; err != nil {
+				return err
+			}
+			for i := range answers {
+				answers[i].ApplicationID = application.ID
+			}
+			if len(answers) > 0 {
+				if err := tx.Create(&answers).Error; err != nil {
+					return err
+				}
+			}
+			changes = append(changes, "updated screening answers")
+		}
+		for i := range documents {
+			var previous []model.Document
+			if err := tx.Where("application_id = ? AND kind = ?", application.ID, documents[i].Kind).Find(&previous).Error; err != nil {
+				return err
+			}
+			if len(previous) > 0 {
+				if err := tx.Delete(&previous).Error; err != nil {
+					return err
+				}
+				replaced = append(replaced, previous...)
+				changes = append(changes, "replaced "+documentLabel(documents[i].Kind)+" "+previous[0].FileName+" with "+documents[i].FileName)
+			} else {
+				changes = append(changes, "added "+documentLabel(documents[i].Kind)+" "+documents[i].FileName)
+			}
+			documents[i].ApplicationID = application.ID
+			if err := tx.Create(&documents[i]).Error; err != nil {
+				return err
+			}
+		}
+
+		stageName := helpers.StageName(application.Stage)
+		history := []model.ApplicationStatusChange{{
+			ApplicationID: application.ID,
+			Event:         model.HistoryEdit,
+			FromStatus:    model.ApplicationApplied,
+			ToStatus:      model.ApplicationApplied,
+			FromStage:     stageName,
+			ToStage:       stageName,
+			ActorType:     model.ActorUser,
+			ActorID:       userID,
+			Note:          capitalize(strings.Join(changes, "; ")),
+		}}
+		// New answers can knock the application out just like on submission
+		if knockedOut {
+			rejected := helpers.FirstStage(job.Stages, model.ApplicationRejected)
+			if err := tx.Model(&model.Application{}).Where("id = ?", application.ID).
+				Updates(map[string]interface{}{"status": model.ApplicationRejected, "stage_id": helpers.StageID(rejected)}).Error; err != nil {
+				return err
+			}
+			history = append(history, model.ApplicationStatusChange{
+				ApplicationID: application.ID,
+				Event:         model.HistoryStatusChange,
+				FromStatus:    model.ApplicationApplied,
+				ToStatus:      model.ApplicationRejected,
+				FromStage:     stageName,
+				ToStage:       helpers.StageName(rejected),
+				ActorType:     model.ActorSystem,
+				Note:          "Rejected by a screening question",
+			})
+		}
+		return tx.Create(&history).Error
+	})
+	if err != nil {
+		helpers.DeleteDocuments(ctx, initializer.Storage, documents)
+		if errors.Is(err, helpers.ErrStatusConflict) {
+			c.JSON(http.StatusConflict, gin.H{"error": "Applications can only be edited until the company starts reviewing them"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update application"})
+		return
+	}
+	helpers.DeleteDocuments(ctx, initializer.Storage, replaced)
+	if knockedOut {
+		helpers.RecordAudit(nil, helpers.AuditEntry{
+			Action:     model.AuditApplicationStatus,
+			EntityType: "application",
+			EntityID:   application.ID,
+			Before:     gin.H{"status": model.ApplicationApplied},
+			After:      gin.H{"status": model.ApplicationRejected, "reason": "knockout"},
+		})
+	}
+
+	initializer.DB.Preload("Answers").Preload("Documents").First(&application, application.ID)
+	helpers.SignDocumentURLs(application.Documents, model.ActorUser, userID)
+	c.JSON(http.StatusOK, gin.H{"message": "Application updated successfully", "application": application})
+}
+
+// userApplication loads the application named by the id parameter if it
+// belongs to the authenticated user. It writes the error response itself
+// and reports false otherwise.
+func userApplication(c *gin.Context) (uint, model.Application, bool) {
+	var application model.Application
+	user, ok := c.Get("user")
+	if !ok || user == nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not found"})
+		return 0, application, false
+	}
+	userModel, ok := user.(model.User)
+	if !ok || userModel.ID == 0 {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid user ID"})
+		return 0, application, false
+	}
+	applicationID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid application ID"})
+		return 0, application, false
+	}
+	if err := initializer.DB.Preload("Stage").Where("user_id = ?", userModel.ID).First(&application, applicationID).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Application not found"})
+		return 0, application, false
+	}
+	return userModel.ID, application, true
+}
+
+// documentLabel names a document kind in history notes
+func documentLabel(kind string) string {
+	return strings.ReplaceAll(kind, "_", " ")
+}
+
+// capitalize upper-cases the first letter of a note
+func capitalize(s string) string {
+	if s == "" {
+		return s
+	}
+	return strings.ToUpper(s[:1]) + s[1:]
+}
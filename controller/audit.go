@@ -0,0 +1,92 @@
+package controller
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sahilq312/workly/helpers"
+	"github.com/sahilq312/workly/initializer"
+	"github.com/sahilq312/workly/model"
+)
+
+// GetAuditLogs lists audit log entries filtered by entity, actor and time range
+func GetAuditLogs(c *gin.Context) {
+	page := 1
+	perPage := 50
+	if pageStr := c.Query("page"); pageStr != "" {
+		if p, err := strconv.Atoi(pageStr); err == nil && p > 0 {
+			page = p
+		}
+	}
+
+	query := initializer.DB.Model(&model.AuditLog{})
+
+	if entityType := c.Query("entity_type"); entityType != "" {
+		query = query.Where("entity_type = ?", entityType)
+	}
+	if entityID := c.Query("entity_id"); entityID != "" {
+		id, err := strconv.ParseUint(entityID, 10, 32)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid entity_id"})
+			return
+		}
+		query = query.Where("entity_id = ?", uint(id))
+	}
+	if actorType := c.Query("actor_type"); actorType != "" {
+		query = query.Where("actor_type = ?", actorType)
+	}
+	if actorID := c.Query("actor_id"); actorID != "" {
+		id, err := strconv.ParseUint(actorID, 10, 32)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid actor_id"})
+			return
+		}
+		query = query.Where("actor_id = ?", uint(id))
+	}
+	if action := c.Query("action"); action != "" {
+		query = query.Where("action = ?", action)
+	}
+	if from := c.Query("from"); from != "" {
+		t, err := time.Parse(time.RFC3339, from)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "from must be an RFC3339 timestamp"})
+			return
+		}
+		query = query.Where("created_at >= ?", t)
+	}
+	if to := c.Query("to"); to != "" {
+		t, err := time.Parse(time.RFC3339, to)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "to must be an RFC3339 timestamp"})
+			return
+		}
+		query = query.Where("created_at < ?", t)
+	}
+
+	var totalRows int64
+	if err := query.Count(&totalRows).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch audit logs"})
+		return
+	}
+
+	var logs []model.AuditLog
+	if err := query.Order("created_at DESC, id DESC").Offset((page - 1) * perPage).Limit(perPage).Find(&logs).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch audit logs"})
+		return
+	}
+
+	helpers.RecordAudit(c, helpers.AuditEntry{
+		Action:     model.AuditAdminQuery,
+		EntityType: "audit_log",
+		After:      c.Request.URL.Query(),
+	})
+
+	c.JSON(http.StatusOK, gin.H{
+		"logs":       logs,
+		"page":       page,
+		"totalRows":  totalRows,
+		"totalPages": (totalRows + int64(perPage) - 1) / int64(perPage),
+	})
+}
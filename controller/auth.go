@@ -2,11 +2,11 @@ package controller
 
 import (
 	"net/http"
-	"os"
 	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/golang-jwt/jwt/v5"
+	"github.com/sahilq312/workly/helpers"
 	"github.com/sahilq312/workly/initializer"
 	"github.com/sahilq312/workly/model"
 	"github.com/sahilq312/workly/utils"
@@ -31,6 +31,11 @@ func Login(c *gin.Context) {
 	var user model.User
 	result := initializer.DB.Where("email = ?", body.Email).First(&user)
 	if result.Error != nil {
+		helpers.RecordAudit(c, helpers.AuditEntry{
+			ActorType: model.ActorUser,
+			Action:    model.AuditUserLoginFailed,
+			After:     gin.H{"email": body.Email, "reason": "unknown email"},
+		})
 		c.JSON(http.StatusUnauthorized, gin.H{"error": "User does not exist"})
 		return
 	}
@@ -38,6 +43,14 @@ func Login(c *gin.Context) {
 	// Compare the provided password with the user's password
 	match, err := utils.CompareHashedPassword(body.Password, user.Password)
 	if err != nil || !match {
+		helpers.RecordAudit(c, helpers.AuditEntry{
+			ActorType:  model.ActorUser,
+			ActorID:    user.ID,
+			Action:     model.AuditUserLoginFailed,
+			EntityType: "user",
+			EntityID:   user.ID,
+			After:      gin.H{"reason": "invalid password"},
+		})
 		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid password"})
 		return
 	}
@@ -45,27 +58,27 @@ func Login(c *gin.Context) {
 	// Generate JWT token
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
 		"user_id": user.ID,
-		"exp":     time.Now().Add(time.Hour * 24).Unix(),
+		"exp":     time.Now().Add(initializer.Config.Auth.UserTokenTTL).Unix(),
 		"iat":     time.Now().Unix(),
 	})
 
-	// Retrieve the JWT secret from environment variables
-	secret := os.Getenv("JWT_SECRET")
-	if secret == "" {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "JWT secret not set"})
-		return
-	}
-
 	// Sign the token with the secret
-	tokenString, err := token.SignedString([]byte(secret))
+	tokenString, err := token.SignedString([]byte(initializer.Config.Auth.UserSecret))
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error in generating JWT token"})
 		return
 	}
 
 	// Set the JWT token as a cookie
-	c.SetSameSite(http.SameSiteLaxMode)
-	c.SetCookie("Authorization", tokenString, 3600*24*30, "/", "", false, true)
+	setAuthCookie(c, "Authorization", tokenString, initializer.Config.Auth.UserTokenTTL)
+
+	helpers.RecordAudit(c, helpers.AuditEntry{
+		ActorType:  model.ActorUser,
+		ActorID:    user.ID,
+		Action:     model.AuditUserLogin,
+		EntityType: "user",
+		EntityID:   user.ID,
+	})
 
 	// Return the user details as a response
 	c.JSON(http.StatusOK, gin.H{
@@ -125,25 +138,18 @@ func Register(c *gin.Context) {
 	// Generate JWT token for the new user
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
 		"user_id": user.ID,
-		"exp":     time.Now().Add(time.Hour * 24).Unix(),
+		"exp":     time.Now().Add(initializer.Config.Auth.UserTokenTTL).Unix(),
 		"iat":     time.Now().Unix(),
 	})
 
-	secret := os.Getenv("JWT_SECRET")
-	if secret == "" {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "JWT secret not set"})
-		return
-	}
-
-	tokenString, err := token.SignedString([]byte(secret))
+	tokenString, err := token.SignedString([]byte(initializer.Config.Auth.UserSecret))
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error in generating JWT token"})
 		return
 	}
 
 	// Set the JWT token as a cookie
-	c.SetSameSite(http.SameSiteLaxMode)
-	c.SetCookie("Authorization", tokenString, 3600*24*30, "/", "", false, true)
+	setAuthCookie(c, "Authorization", tokenString, initializer.Config.Auth.UserTokenTTL)
 
 	// Return the user details and session
 	c.JSON(http.StatusOK, gin.H{
@@ -202,10 +208,22 @@ func GetUser(c *gin.Context) {
 
 func Logout(c *gin.Context) {
 	// Delete the JWT token cookie
-	c.SetCookie("Authorization", "", -1, "/", "", false, true)
+	setAuthCookie(c, "Authorization", "", -1)
 
 	// Return success message
 	c.JSON(http.StatusOK, gin.H{
 		"data": "Logged out successfully",
 	})
 }
+
+// setAuthCookie writes an HTTP-only session cookie using the configured cookie flags.
+// A negative ttl deletes the cookie.
+func setAuthCookie(c *gin.Context, name, value string, ttl time.Duration) {
+	auth := initializer.Config.Auth
+	maxAge := int(ttl.Seconds())
+	if ttl < 0 {
+		maxAge = -1
+	}
+	c.SetSameSite(auth.SameSite())
+	c.SetCookie(name, value, maxAge, "/", auth.CookieDomain, auth.CookieSecure, true)
+}
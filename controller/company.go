@@ -3,18 +3,21 @@ package controller
 import (
 	"errors"
 	"net/http"
-	"os"
 	"strconv"
 	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/golang-jwt/jwt/v5"
+	"github.com/sahilq312/workly/helpers"
 	"github.com/sahilq312/workly/initializer"
 	"github.com/sahilq312/workly/model"
 	"github.com/sahilq312/workly/utils"
 	"gorm.io/gorm"
 )
 
+// Longest re-apply cool-down a company may set, in days
+const maxReapplyCooldownDays = 3650
+
 // CreateCompany creates a new company
 func CreateCompany(c *gin.Context) {
 	var body struct {
@@ -85,6 +88,11 @@ func LoginCompany(c *gin.Context) {
 	var company model.Company
 	result := initializer.DB.Where("email = ?", body.Email).First(&company)
 	if result.Error != nil {
+		helpers.RecordAudit(c, helpers.AuditEntry{
+			ActorType: model.ActorCompany,
+			Action:    model.AuditCompanyLoginFailed,
+			After:     gin.H{"email": body.Email, "reason": "unknown email"},
+		})
 		c.JSON(http.StatusUnauthorized, gin.H{"error": "Company does not exist"})
 		return
 	}
@@ -92,6 +100,14 @@ func LoginCompany(c *gin.Context) {
 	// Validate password
 	match, err := utils.CompareHashedPassword(body.Password, company.Password)
 	if err != nil || !match {
+		helpers.RecordAudit(c, helpers.AuditEntry{
+			ActorType:  model.ActorCompany,
+			ActorID:    company.ID,
+			Action:     model.AuditCompanyLoginFailed,
+			EntityType: "company",
+			EntityID:   company.ID,
+			After:      gin.H{"reason": "invalid password"},
+		})
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid Password"})
 		return
 	}
@@ -99,19 +115,26 @@ func LoginCompany(c *gin.Context) {
 	// Generate JWT token
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
 		"company_id": company.ID,
-		"exp":        time.Now().Add(time.Hour * 24).Unix(),
+		"exp":        time.Now().Add(initializer.Config.Auth.CompanyTokenTTL).Unix(),
 		"iat":        time.Now().Unix(),
 	})
 
-	tokenString, err := token.SignedString([]byte(os.Getenv("JWT_COMPANY_SECRET")))
+	tokenString, err := token.SignedString([]byte(initializer.Config.Auth.CompanySecret))
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error generating JWT token"})
 		return
 	}
 
 	// Set the cookie with token
-	c.SetSameSite(http.SameSiteLaxMode)
-	c.SetCookie("CompanyAuth", tokenString, 86400, "/", "", false, true) // Cookie duration matches token expiration
+	setAuthCookie(c, "CompanyAuth", tokenString, initializer.Config.Auth.CompanyTokenTTL) // Cookie duration matches token expiration
+
+	helpers.RecordAudit(c, helpers.AuditEntry{
+		ActorType:  model.ActorCompany,
+		ActorID:    company.ID,
+		Action:     model.AuditCompanyLogin,
+		EntityType: "company",
+		EntityID:   company.ID,
+	})
 
 	c.JSON(http.StatusOK, gin.H{
 		"data": company,
@@ -145,7 +168,7 @@ func GetCompanyById(c *gin.Context) {
 
 	var company model.Company
 	// Retrieve company by ID
-	if err := initializer.DB.First(&company, uint(companyID)).Error; err != nil {
+	if err := initializer.Reader(c.Request.Context()).First(&company, uint(companyID)).Error; err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			c.JSON(http.StatusNotFound, gin.H{"error": "Company not found"})
 			return
@@ -176,11 +199,19 @@ func UpdateCompany(c *gin.Context) {
 		Logo    string `json:"logo"`
 		Email   string `json:"email"`
 		Address string `json:"address"`
+		// Days a rejected candidate waits before re-applying; negative for never
+		ReapplyCooldownDays *int `json:"reapply_cooldown_days"`
 	}
 	if err := c.BindJSON(&body); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request"})
 		return
 	}
+	if body.ReapplyCooldownDays != nil && *body.ReapplyCooldownDays > maxReapplyCooldownDays {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "reapply_cooldown_days must be at most 3650"})
+		return
+	}
+
+	before := companyModel
 
 	// Update company fields
 	if body.Name != "" {
@@ -195,12 +226,26 @@ func UpdateCompany(c *gin.Context) {
 	if body.Address != "" {
 		companyModel.Address = body.Address
 	}
+	if body.ReapplyCooldownDays != nil {
+		companyModel.ReapplyCooldownDays = *body.ReapplyCooldownDays
+		if companyModel.ReapplyCooldownDays < 0 {
+			companyModel.ReapplyCooldownDays = -1
+		}
+	}
 
 	if result := initializer.DB.Save(&companyModel); result.Error != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update company"})
 		return
 	}
 
+	helpers.RecordAudit(c, helpers.AuditEntry{
+		Action:     model.AuditCompanyUpdate,
+		EntityType: "company",
+		EntityID:   companyModel.ID,
+		Before:     before,
+		After:      companyModel,
+	})
+
 	c.JSON(http.StatusOK, gin.H{"message": "Company updated successfully", "company": companyModel})
 }
 
@@ -229,7 +274,7 @@ func DeleteCompany(c *gin.Context) {
 func GetAllCompanies(c *gin.Context) {
 	var companies []model.Company
 	// Fetch companies from the database
-	if err := initializer.DB.Find(&companies).Error; err != nil {
+	if err := initializer.Reader(c.Request.Context()).Find(&companies).Error; err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get companies"})
 		return
 	}
@@ -264,8 +309,23 @@ func GetCompanyJobById(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid job ID"})
 		return
 	}
+	company, ok := c.Get("company")
+	if !ok || company == nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Company not found"})
+		return
+	}
+	companyModel, ok := company.(model.Company)
+	if !ok || companyModel.ID == 0 {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized access"})
+		return
+	}
+	// Includes the knockout rules of the screening questions, so only the owner may see it
 	var job model.Job
-	if err := initializer.DB.First(&job, uint(id)).Error; err != nil {
+	if err := initializer.DB.
+		Preload("Questions", orderByPosition).Preload("Questions.Options", orderByPosition).
+		Preload("Stages", orderByPosition).
+		Where("company_id = ?", companyModel.ID).
+		First(&job, uint(id)).Error; err != nil {
 		c.JSON(http.StatusNotFound, gin.H{"error": "Job not found"})
 		return
 	}
@@ -0,0 +1,77 @@
+package controller
+
+import (
+	"errors"
+	"io"
+	"log"
+	"mime"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sahilq312/workly/helpers"
+	"github.com/sahilq312/workly/initializer"
+	"github.com/sahilq312/workly/model"
+	"github.com/sahilq312/workly/storage"
+)
+
+// DownloadDocument streams an application document through a signed link.
+// The link names the user or company it was issued to, and access is checked
+// again so a link stops working once that principal loses access.
+func DownloadDocument(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid document ID"})
+		return
+	}
+	actorType, actorID, err := helpers.VerifyDocumentURL(uint(id), c.Request.URL.Query())
+	if err != nil {
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx := c.Request.Context()
+	var document model.Document
+	if err := initializer.Reader(ctx).First(&document, id).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Document not found"})
+		return
+	}
+	var application model.Application
+	if err := initializer.Reader(ctx).Preload("Job").First(&application, document.ApplicationID).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Document not found"})
+		return
+	}
+	allowed := false
+	switch actorType {
+	case model.ActorUser:
+		allowed = application.UserID == actorID
+	case model.ActorCompany:
+		allowed = application.Job.ID != 0 && application.Job.CompanyID == actorID
+	}
+	if !allowed {
+		c.JSON(http.StatusForbidden, gin.H{"error": "You do not have access to this document"})
+		return
+	}
+
+	file, err := initializer.Storage.Get(ctx, document.StorageKey)
+	if errors.Is(err, storage.ErrNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Document not found"})
+		return
+	}
+	if err != nil {
+		log.Printf("storage: failed to read %s: %v", document.StorageKey, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch document"})
+		return
+	}
+	defer file.Close()
+
+	c.Header("Content-Type", document.ContentType)
+	c.Header("Content-Length", strconv.FormatInt(document.Size, 10))
+	c.Header("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": document.FileName}))
+	c.Header("Cache-Control", "private, no-store")
+	c.Header("X-Content-Type-Options", "nosniff")
+	c.Status(http.StatusOK)
+	if _, err := io.Copy(c.Writer, file); err != nil {
+		log.Printf("storage: failed to stream %s: %v", document.StorageKey, err)
+	}
+}
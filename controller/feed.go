@@ -0,0 +1,160 @@
+package controller
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sahilq312/workly/feed"
+	"github.com/sahilq312/workly/helpers"
+	"github.com/sahilq312/workly/initializer"
+	"github.com/sahilq312/workly/model"
+	"gorm.io/gorm/clause"
+)
+
+const feedPublisher = "Workly"
+
+// How long clients and proxies may reuse a feed before revalidating it
+const feedMaxAge = 15 * time.Minute
+
+// GetJobFeed serves recently published jobs as an RSS, Atom or aggregator XML
+// feed. The feed can be scoped to a company, skill or location through the
+// path, and accepts the same filters as GetAllJobs in the query string.
+func GetJobFeed(c *gin.Context) {
+	format := strings.TrimSuffix(c.Param("format"), ".xml")
+	contentType, ok := feed.ContentTypes[format]
+	if !ok {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Feed format must be rss, atom or jobs"})
+		return
+	}
+	limit := 50
+	if limitStr := c.Query("limit"); limitStr != "" {
+		if l, err := strconv.Atoi(limitStr); err == nil && l > 0 && l <= 100 {
+			limit = l
+		}
+	}
+
+	// Path scopes become ordinary filters, so the feed links back to the same listing
+	query := c.Request.URL.Query()
+	query.Del("limit")
+	query.Del("page")
+	query.Del("sort")
+	title := "Latest jobs"
+	db := initializer.Reader(c.Request.Context())
+	if c.Param("id") != "" {
+		var company model.Company
+		companyID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+		if err == nil {
+			err = db.First(&company, companyID).Error
+		}
+		if err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Company not found"})
+			return
+		}
+		query.Set("company_ids", strconv.FormatUint(uint64(company.ID), 10))
+		title = "Jobs at " + company.Name
+	}
+	if skill := strings.TrimSpace(c.Param("skill")); skill != "" {
+		query.Set("skills", skill)
+		title = skill + " jobs"
+	}
+	if location := strings.TrimSpace(c.Param("location")); location != "" {
+		query.Set("location", location)
+		title = "Jobs in " + location
+	}
+	filter, err := helpers.ParseJobFilterQuery(query)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Validators cover every matching job, so a new, edited or removed job changes them
+	var stats struct {
+		Count   int64
+		Updated *time.Time
+	}
+	err = filter.Apply(db.Model(&model.Job{})).
+		Select("COUNT(*) AS count, MAX(GREATEST(jobs.updated_at, jobs.published_at)) AS updated").
+		Scan(&stats).Error
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to build feed"})
+		return
+	}
+	lastModified := time.Now().UTC().Truncate(time.Second)
+	if stats.Updated != nil {
+		lastModified = stats.Updated.UTC().Truncate(time.Second)
+	}
+	etag := feedETag(format, limit, query, stats.Count, lastModified)
+
+	c.Header("Cache-Control", fmt.Sprintf("public, max-age=%d", int(feedMaxAge.Seconds())))
+	c.Header("ETag", etag)
+	c.Header("Last-Modified", lastModified.Format(http.TimeFormat))
+	c.Header("Vary", "Accept-Encoding")
+	if feedNotModified(c, etag, lastModified) {
+		c.Status(http.StatusNotModified)
+		return
+	}
+
+	var jobs []model.Job
+	err = filter.Apply(db.Model(&model.Job{})).
+		Preload("Company").Preload("Skills").Preload("Locations").Preload("Category").
+		Order(clause.OrderBy{Expression: clause.Expr{SQL: "COALESCE(jobs.published_at, jobs.created_at) DESC, jobs.id DESC"}}).
+		Limit(limit).
+		Find(&jobs).Error
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to build feed"})
+		return
+	}
+	for i := range jobs {
+		jobs[i].Redact()
+	}
+
+	listing := helpers.PublicURL("/job/")
+	if encoded := query.Encode(); encoded != "" {
+		listing += "?" + encoded
+	}
+	body, err := feed.Render(format, feed.Meta{
+		Title:       feedPublisher + ": " + title,
+		Description: title + " on " + feedPublisher,
+		Link:        listing,
+		Self:        helpers.PublicURL(c.Request.URL.RequestURI()),
+		Publisher:   feedPublisher,
+		Updated:     lastModified,
+		JobURL:      helpers.JobURL,
+	}, jobs)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to build feed"})
+		return
+	}
+	c.Data(http.StatusOK, contentType, body)
+}
+
+// feedETag identifies one version of a feed
+func feedETag(format string, limit int, query url.Values, count int64, updated time.Time) string {
+	sum := sha256.Sum256([]byte(fmt.Sprintf("%s|%d|%s|%d|%d", format, limit, query.Encode(), count, updated.Unix())))
+	return `"` + hex.EncodeToString(sum[:8]) + `"`
+}
+
+// feedNotModified applies the conditional request headers. If-None-Match
+// takes precedence over If-Modified-Since, as RFC 9110 requires.
+func feedNotModified(c *gin.Context, etag string, lastModified time.Time) bool {
+	if match := c.GetHeader("If-None-Match"); match != "" {
+		for _, candidate := range strings.Split(match, ",") {
+			candidate = strings.TrimPrefix(strings.TrimSpace(candidate), "W/")
+			if candidate == etag || candidate == "*" {
+				return true
+			}
+		}
+		return false
+	}
+	if since, err := http.ParseTime(c.GetHeader("If-Modified-Since")); err == nil {
+		return !lastModified.After(since)
+	}
+	return false
+}
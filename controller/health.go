@@ -0,0 +1,189 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sahilq312/workly/health"
+	"github.com/sahilq312/workly/initializer"
+	"github.com/sahilq312/workly/model"
+	"gorm.io/gorm"
+)
+
+// Budget for all dependency checks in a single readiness probe
+const readinessTimeout = 2 * time.Second
+
+// Set once the schema check passes; the schema only changes with a deploy
+var migrationsChecked atomic.Bool
+
+type componentStatus struct {
+	Status  string      `json:"status"`
+	Error   string      `json:"error,omitempty"`
+	Details interface{} `json:"details,omitempty"`
+}
+
+// Livez reports that the process is running. It never touches dependencies,
+// so a slow database does not get the pod restarted.
+func Livez(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"status": "ok"})
+}
+
+// Readyz reports whether the service can take traffic, with per-component detail
+func Readyz(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c.Request.Context(), readinessTimeout)
+	defer cancel()
+
+	components := gin.H{
+		"database":   checkDatabase(ctx),
+		"migrations": checkMigrations(ctx),
+		"workers":    checkWorkers(),
+	}
+
+	ready := !health.ShuttingDown()
+	for _, component := range components {
+		if component.(componentStatus).Status != "ok" {
+			ready = false
+		}
+	}
+
+	status := http.StatusOK
+	state := "ready"
+	if health.ShuttingDown() {
+		state = "shutting_down"
+	}
+	if !ready {
+		status = http.StatusServiceUnavailable
+		if state == "ready" {
+			state = "not_ready"
+		}
+	}
+	c.JSON(status, gin.H{"status": state, "components": components})
+}
+
+// checkDatabase pings the primary and every replica. Only the primary is
+// required; an unreachable replica is reported without failing readiness.
+func checkDatabase(ctx context.Context) componentStatus {
+	var details []gin.H
+	result := componentStatus{Status: "ok"}
+	for i, db := range initializer.Databases() {
+		name := "primary"
+		if i > 0 {
+			name = fmt.Sprintf("replica-%d", i)
+		}
+		entry := gin.H{"name": name, "status": "ok"}
+		sqlDB, err := db.DB()
+		if err == nil {
+			err = sqlDB.PingContext(ctx)
+		}
+		if err != nil {
+			log.Printf("readiness: %s database: %v", name, err)
+			entry["status"] = "error"
+			if i == 0 {
+				result.Status = "error"
+				result.Error = "primary database unreachable"
+			}
+		} else {
+			stats := sqlDB.Stats()
+			entry["open_connections"] = stats.OpenConnections
+			entry["in_use"] = stats.InUse
+		}
+		details = append(details, entry)
+	}
+	result.Details = details
+	return result
+}
+
+// checkMigrations verifies that the tables, columns and indexes of every
+// model exist, together with the search columns, indexes and triggers the
+// migrate tool adds with SQL. Missing objects are logged; the probe only
+// reports that migrations are pending. A passing check is remembered, so
+// later probes do not query the catalog again.
+func checkMigrations(ctx context.Context) componentStatus {
+	if migrationsChecked.Load() {
+		return componentStatus{Status: "ok"}
+	}
+	db := initializer.DB.WithContext(ctx)
+	var columns []struct {
+		TableName  string
+		ColumnName string
+	}
+	var indexes, triggers []string
+	err := db.Raw("SELECT table_name, column_name FROM information_schema.columns WHERE table_schema = current_schema()").Scan(&columns).Error
+	if err == nil {
+		err = db.Raw("SELECT indexname FROM pg_indexes WHERE schemaname = current_schema()").Scan(&indexes).Error
+	}
+	if err == nil {
+		err = db.Raw(`SELECT t.tgname FROM pg_trigger t
+			JOIN pg_class c ON c.oid = t.tgrelid
+			JOIN pg_namespace n ON n.oid = c.relnamespace
+			WHERE NOT t.tgisinternal AND n.nspname = current_schema()`).Scan(&triggers).Error
+	}
+	if err != nil {
+		log.Printf("readiness: reading the schema failed: %v", err)
+		return componentStatus{Status: "error", Error: "schema check failed"}
+	}
+
+	have := map[string]bool{}
+	for _, column := range columns {
+		have[column.TableName+"."+column.ColumnName] = true
+	}
+	for _, name := range append(indexes, triggers...) {
+		have[name] = true
+	}
+	var missing []string
+	require := func(name string) {
+		if !have[name] {
+			missing = append(missing, name)
+		}
+	}
+	for _, m := range model.AllModels() {
+		stmt := &gorm.Statement{DB: initializer.DB}
+		if err := stmt.Parse(m); err != nil {
+			missing = append(missing, fmt.Sprintf("%T", m))
+			continue
+		}
+		for _, field := range stmt.Schema.Fields {
+			if field.DBName != "" && !field.IgnoreMigration {
+				require(stmt.Schema.Table + "." + field.DBName)
+			}
+		}
+		for name := range stmt.Schema.ParseIndexes() {
+			require(name)
+		}
+	}
+	for table, names := range model.MigratedColumns {
+		for _, name := range names {
+			require(table + "." + name)
+		}
+	}
+	for _, name := range append(model.MigratedIndexes, model.MigratedTriggers...) {
+		require(name)
+	}
+
+	if len(missing) > 0 {
+		log.Printf("readiness: missing schema objects: %s", strings.Join(missing, ", "))
+		return componentStatus{Status: "error", Error: "pending migrations"}
+	}
+	migrationsChecked.Store(true)
+	return componentStatus{Status: "ok"}
+}
+
+func checkWorkers() componentStatus {
+	workers := health.Workers()
+	result := componentStatus{Status: "ok", Details: workers}
+	for i, w := range workers {
+		// Errors may quote the database; workers already log them
+		workers[i].LastError = ""
+		if !w.Healthy {
+			result.Status = "error"
+			result.Error = fmt.Sprintf("worker %s is not reporting", w.Name)
+		}
+	}
+	return result
+}
@@ -0,0 +1,598 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+	"net/mail"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sahilq312/workly/helpers"
+	"github.com/sahilq312/workly/ical"
+	"github.com/sahilq312/workly/initializer"
+	"github.com/sahilq312/workly/model"
+	"github.com/sahilq312/workly/utils"
+	"gorm.io/gorm"
+)
+
+// Cancelled interviews stay in interviewer feeds for this long so calendars
+// pick up the cancellation
+const interviewFeedHistory = 30 * 24 * time.Hour
+
+// errInterviewChanged is returned when an interview changed while an update
+// was being applied
+var errInterviewChanged = errors.New("interview was changed by someone else")
+
+// CreateInterviewer adds a member of the company's hiring team
+func CreateInterviewer(c *gin.Context) {
+	companyID, ok := pipelineCompanyID(c)
+	if !ok {
+		return
+	}
+	var body struct {
+		Name  string `json:"name"`
+		Email string `json:"email"`
+	}
+	if err := c.ShouldBindJSON(&body); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+	interviewer := model.Interviewer{CompanyID: companyID, Name: strings.TrimSpace(body.Name)}
+	if interviewer.Name == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Name is required"})
+		return
+	}
+	address, err := mail.ParseAddress(strings.TrimSpace(body.Email))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "A valid email is required"})
+		return
+	}
+	interviewer.Email = address.Address
+	if interviewer.FeedToken, err = utils.RandomToken(24); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create interviewer"})
+		return
+	}
+	if err := initializer.DB.Create(&interviewer).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create interviewer"})
+		return
+	}
+	interviewers := []model.Interviewer{interviewer}
+	helpers.SetInterviewerFeedURLs(interviewers)
+	c.JSON(http.StatusCreated, gin.H{"message": "Interviewer created successfully", "data": interviewers[0]})
+}
+
+// GetInterviewers lists the company's interviewers with their calendar feed links
+func GetInterviewers(c *gin.Context) {
+	companyID, ok := pipelineCompanyID(c)
+	if !ok {
+		return
+	}
+	interviewers := []model.Interviewer{}
+	if err := initializer.DB.Where("company_id = ?", companyID).Order("name").Find(&interviewers).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch interviewers"})
+		return
+	}
+	helpers.SetInterviewerFeedURLs(interviewers)
+	c.JSON(http.StatusOK, gin.H{"data": interviewers})
+}
+
+// DeleteInterviewer removes an interviewer; their feed link stops working
+func DeleteInterviewer(c *gin.Context) {
+	companyID, ok := pipelineCompanyID(c)
+	if !ok {
+		return
+	}
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid interviewer ID"})
+		return
+	}
+	result := initializer.DB.Where("company_id = ?", companyID).Delete(&model.Interviewer{}, id)
+	if result.Error != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete interviewer"})
+		return
+	}
+	if result.RowsAffected == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Interviewer not found"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Interviewer deleted successfully"})
+}
+
+// CreateInterview offers the candidate of one of the company's applications a
+// choice of interview slots. The candidate is notified and books one of them.
+func CreateInterview(c *gin.Context) {
+	companyID, ok := pipelineCompanyID(c)
+	if !ok {
+		return
+	}
+	var body struct {
+		ApplicationID uint `json:"application_id"`
+		helpers.InterviewInput
+	}
+	if err := c.ShouldBindJSON(&body); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+	if body.ApplicationID == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "application_id is required"})
+		return
+	}
+	application, ok := companyApplication(c, companyID, int(body.ApplicationID))
+	if !ok {
+		return
+	}
+	if model.IsTerminalApplicationStatus(application.Status) {
+		c.JSON(http.StatusConflict, gin.H{"error": "Cannot schedule an interview for a " + application.Status + " application"})
+		return
+	}
+	interview, err := helpers.BuildInterview(body.InterviewInput, time.Now())
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if len(body.InterviewerIDs) > 0 {
+		if err := initializer.DB.Where("company_id = ? AND id IN ?", companyID, body.InterviewerIDs).Find(&interview.Interviewers).Error; err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create interview"})
+			return
+		}
+		if len(interview.Interviewers) != len(uniqueIDs(body.InterviewerIDs)) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Unknown interviewer in interviewer_ids"})
+			return
+		}
+	}
+	interview.ApplicationID = application.ID
+	if err := initializer.DB.Create(&interview).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create interview"})
+		return
+	}
+	if err := preloadInterview(initializer.DB).First(&interview, interview.ID).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create interview"})
+		return
+	}
+
+	helpers.NotifyInterviewCandidate(interview, "Pick a time for your interview with "+interview.Application.Job.Company.Name)
+	helpers.RecordAudit(c, helpers.AuditEntry{
+		Action:     model.AuditInterviewCreate,
+		EntityType: "interview",
+		EntityID:   interview.ID,
+		After:      interview,
+	})
+	c.JSON(http.StatusCreated, gin.H{"message": "Interview created successfully", "data": interview})
+}
+
+// GetCompanyInterviews lists interviews for the company's applications.
+// Optional query parameters: application_id, job_id and status.
+func GetCompanyInterviews(c *gin.Context) {
+	companyID, ok := pipelineCompanyID(c)
+	if !ok {
+		return
+	}
+	query := initializer.Reader(c.Request.Context()).
+		Preload("Slots", orderByStart).Preload("Interviewers").
+		Joins("JOIN applications ON applications.id = interviews.application_id").
+		Joins("JOIN jobs ON jobs.id = applications.job_id").
+		Where("jobs.company_id = ?", companyID)
+	for param, column := range map[string]string{"application_id": "interviews.application_id", "job_id": "applications.job_id"} {
+		if value := c.Query(param); value != "" {
+			id, err := strconv.ParseUint(value, 10, 32)
+			if err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid " + param})
+				return
+			}
+			query = query.Where(column+" = ?", id)
+		}
+	}
+	if status := c.Query("status"); status != "" {
+		query = query.Where("interviews.status = ?", status)
+	}
+	interviews := []model.Interview{}
+	if err := query.Order("interviews.starts_at NULLS LAST, interviews.id").Find(&interviews).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch interviews"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": interviews})
+}
+
+// RescheduleInterview replaces the slots offered for an interview. A booked
+// time is released and its invite cancelled, and the candidate picks again.
+func RescheduleInterview(c *gin.Context) {
+	companyID, ok := pipelineCompanyID(c)
+	if !ok {
+		return
+	}
+	var body struct {
+		Slots []time.Time `json:"slots"`
+	}
+	if err := c.ShouldBindJSON(&body); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+	interview, ok := companyInterview(c, companyID)
+	if !ok {
+		return
+	}
+	if interview.Status == model.InterviewCancelled {
+		c.JSON(http.StatusConflict, gin.H{"error": "Interview has been cancelled"})
+		return
+	}
+	slots, err := helpers.BuildInterviewSlots(body.Slots, time.Now())
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	previous := interview
+	err = initializer.DB.Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&model.Interview{}).
+			Where("id = ? AND sequence = ?", interview.ID, interview.Sequence).
+			Updates(map[string]interface{}{
+				"status":    model.InterviewProposed,
+				"slot_id":   nil,
+				"starts_at": nil,
+				"sequence":  interview.Sequence + 1,
+			})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return errInterviewChanged
+		}
+		if err := tx.Where("interview_id = ?", interview.ID).Delete(&model.InterviewSlot{}).Error; err != nil {
+			return err
+		}
+		for i := range slots {
+			slots[i].InterviewID = interview.ID
+		}
+		return tx.Create(&slots).Error
+	})
+	if !respondInterviewUpdate(c, err, "Failed to reschedule interview") {
+		return
+	}
+	preloadInterview(initializer.DB).First(&interview, interview.ID)
+
+	if previous.Status == model.InterviewScheduled {
+		previous.Status, previous.Sequence = model.InterviewCancelled, interview.Sequence
+		helpers.SendInterviewInvites(previous, ical.MethodCancel)
+	}
+	helpers.NotifyInterviewCandidate(interview, "New times offered for your interview with "+interview.Application.Job.Company.Name)
+	helpers.RecordAudit(c, helpers.AuditEntry{
+		Action:     model.AuditInterviewMoved,
+		EntityType: "interview",
+		EntityID:   interview.ID,
+		Before:     gin.H{"status": previous.Status, "starts_at": previous.StartsAt, "slots": previous.Slots},
+		After:      gin.H{"status": interview.Status, "slots": interview.Slots},
+	})
+	c.JSON(http.StatusOK, gin.H{"message": "Interview rescheduled successfully", "data": interview})
+}
+
+// CancelInterviewByCompany cancels an interview with an optional reason
+func CancelInterviewByCompany(c *gin.Context) {
+	companyID, ok := pipelineCompanyID(c)
+	if !ok {
+		return
+	}
+	interview, ok := companyInterview(c, companyID)
+	if !ok {
+		return
+	}
+	if cancelInterview(c, &interview) {
+		helpers.NotifyInterviewCandidate(interview, "Your interview with "+interview.Application.Job.Company.Name+" has been cancelled")
+	}
+}
+
+// GetCompanyInterviewInvite downloads the calendar entry of a booked interview
+func GetCompanyInterviewInvite(c *gin.Context) {
+	companyID, ok := pipelineCompanyID(c)
+	if !ok {
+		return
+	}
+	if interview, ok := companyInterview(c, companyID); ok {
+		writeInterviewInvite(c, interview)
+	}
+}
+
+// GetUserInterviews lists the interviews of the user's applications
+func GetUserInterviews(c *gin.Context) {
+	userID, ok := interviewUserID(c)
+	if !ok {
+		return
+	}
+	interviews := []model.Interview{}
+	if err := initializer.Reader(c.Request.Context()).
+		Preload("Slots", orderByStart).Preload("Interviewers").
+		Joins("JOIN applications ON applications.id = interviews.application_id").
+		Where("applications.user_id = ?", userID).
+		Order("interviews.starts_at NULLS LAST, interviews.id").
+		Find(&interviews).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch interviews"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": interviews})
+}
+
+// BookInterview books one of the offered slots. Booking another slot of a
+// scheduled interview reschedules it, and the invites are updated in place.
+func BookInterview(c *gin.Context) {
+	userID, ok := interviewUserID(c)
+	if !ok {
+		return
+	}
+	var body struct {
+		SlotID uint `json:"slot_id"`
+	}
+	if err := c.ShouldBindJSON(&body); err != nil || body.SlotID == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "slot_id is required"})
+		return
+	}
+	interview, ok := userInterview(c, userID)
+	if !ok {
+		return
+	}
+	if interview.Status == model.InterviewCancelled {
+		c.JSON(http.StatusConflict, gin.H{"error": "Interview has been cancelled"})
+		return
+	}
+	if model.IsTerminalApplicationStatus(interview.Application.Status) {
+		c.JSON(http.StatusConflict, gin.H{"error": "Your application is " + interview.Application.Status})
+		return
+	}
+	var slot *model.InterviewSlot
+	for i := range interview.Slots {
+		if interview.Slots[i].ID == body.SlotID {
+			slot = &interview.Slots[i]
+		}
+	}
+	if slot == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Slot not found for this interview"})
+		return
+	}
+	if !slot.StartsAt.After(time.Now()) {
+		c.JSON(http.StatusConflict, gin.H{"error": "This slot has already passed"})
+		return
+	}
+	if interview.SlotID != nil && *interview.SlotID == slot.ID {
+		c.JSON(http.StatusConflict, gin.H{"error": "You have already booked this slot"})
+		return
+	}
+
+	previous := interview
+	result := initializer.DB.Model(&model.Interview{}).
+		Where("id = ? AND sequence = ?", interview.ID, interview.Sequence).
+		Updates(map[string]interface{}{
+			"status":    model.InterviewScheduled,
+			"slot_id":   slot.ID,
+			"starts_at": slot.StartsAt,
+			"sequence":  interview.Sequence + 1,
+		})
+	err := result.Error
+	if err == nil && result.RowsAffected == 0 {
+		err = errInterviewChanged
+	}
+	if !respondInterviewUpdate(c, err, "Failed to book interview") {
+		return
+	}
+	preloadInterview(initializer.DB).First(&interview, interview.ID)
+
+	helpers.SendInterviewInvites(interview, ical.MethodRequest)
+	action := model.AuditInterviewBook
+	if previous.Status == model.InterviewScheduled {
+		action = model.AuditInterviewMoved
+	}
+	helpers.RecordAudit(c, helpers.AuditEntry{
+		Action:     action,
+		EntityType: "interview",
+		EntityID:   interview.ID,
+		Before:     gin.H{"status": previous.Status, "starts_at": previous.StartsAt},
+		After:      gin.H{"status": interview.Status, "starts_at": interview.StartsAt},
+	})
+	c.JSON(http.StatusOK, gin.H{"message": "Interview booked successfully", "data": interview})
+}
+
+// CancelInterviewByUser lets the candidate cancel an interview with an
+// optional reason
+func CancelInterviewByUser(c *gin.Context) {
+	userID, ok := interviewUserID(c)
+	if !ok {
+		return
+	}
+	if interview, ok := userInterview(c, userID); ok {
+		cancelInterview(c, &interview)
+	}
+}
+
+// GetUserInterviewInvite downloads the calendar entry of a booked interview
+func GetUserInterviewInvite(c *gin.Context) {
+	userID, ok := interviewUserID(c)
+	if !ok {
+		return
+	}
+	if interview, ok := userInterview(c, userID); ok {
+		writeInterviewInvite(c, interview)
+	}
+}
+
+// GetInterviewerFeed serves an interviewer's booked and recently cancelled
+// interviews as an iCalendar feed. The secret token in the URL is the only
+// credential, so calendar apps can subscribe to it.
+func GetInterviewerFeed(c *gin.Context) {
+	token := strings.TrimSuffix(c.Param("token"), ".ics")
+	var interviewer model.Interviewer
+	if token == "" || initializer.DB.Where("feed_token = ?", token).First(&interviewer).Error != nil {
+		c.String(http.StatusNotFound, "Calendar not found")
+		return
+	}
+	var interviews []model.Interview
+	if err := preloadInterview(initializer.Reader(c.Request.Context())).
+		Joins("JOIN interview_interviewers ON interview_interviewers.interview_id = interviews.id").
+		Where("interview_interviewers.interviewer_id = ?", interviewer.ID).
+		Where("interviews.status IN ? AND interviews.starts_at >= ?",
+			[]string{model.InterviewScheduled, model.InterviewCancelled}, time.Now().Add(-interviewFeedHistory)).
+		Order("interviews.starts_at").
+		Find(&interviews).Error; err != nil {
+		c.String(http.StatusInternalServerError, "Failed to fetch interviews")
+		return
+	}
+	c.Header("Cache-Control", "private, max-age=300")
+	c.Data(http.StatusOK, ical.ContentType, helpers.InterviewerFeed(interviewer, interviews))
+}
+
+// cancelInterview cancels an interview for the authenticated principal,
+// sending cancellations when it was booked, and writes the response. It
+// reports whether the interview was cancelled.
+func cancelInterview(c *gin.Context, interview *model.Interview) bool {
+	var body struct {
+		Reason string `json:"reason"`
+	}
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&body); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+			return false
+		}
+	}
+	reason := strings.TrimSpace(body.Reason)
+	if utf8.RuneCountInString(reason) > 1000 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "reason must be at most 1000 characters"})
+		return false
+	}
+	if interview.Status == model.InterviewCancelled {
+		c.JSON(http.StatusConflict, gin.H{"error": "Interview has already been cancelled"})
+		return false
+	}
+
+	previousStatus := interview.Status
+	result := initializer.DB.Model(&model.Interview{}).
+		Where("id = ? AND sequence = ?", interview.ID, interview.Sequence).
+		Updates(map[string]interface{}{
+			"status":        model.InterviewCancelled,
+			"cancel_reason": reason,
+			"sequence":      interview.Sequence + 1,
+		})
+	err := result.Error
+	if err == nil && result.RowsAffected == 0 {
+		err = errInterviewChanged
+	}
+	if !respondInterviewUpdate(c, err, "Failed to cancel interview") {
+		return false
+	}
+	preloadInterview(initializer.DB).First(interview, interview.ID)
+
+	if previousStatus == model.InterviewScheduled {
+		helpers.SendInterviewInvites(*interview, ical.MethodCancel)
+	}
+	helpers.RecordAudit(c, helpers.AuditEntry{
+		Action:     model.AuditInterviewCancel,
+		EntityType: "interview",
+		EntityID:   interview.ID,
+		Before:     gin.H{"status": previousStatus},
+		After:      gin.H{"status": interview.Status, "reason": reason},
+	})
+	c.JSON(http.StatusOK, gin.H{"message": "Interview cancelled successfully", "data": interview})
+	return true
+}
+
+// respondInterviewUpdate writes the error response for a failed interview
+// update and reports whether it succeeded
+func respondInterviewUpdate(c *gin.Context, err error, message string) bool {
+	if errors.Is(err, errInterviewChanged) {
+		c.JSON(http.StatusConflict, gin.H{"error": "Interview was changed by someone else; reload and try again"})
+		return false
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": message})
+		return false
+	}
+	return true
+}
+
+// writeInterviewInvite sends the calendar entry of a booked interview
+func writeInterviewInvite(c *gin.Context, interview model.Interview) {
+	if interview.StartsAt == nil {
+		c.JSON(http.StatusConflict, gin.H{"error": "Interview has not been booked yet"})
+		return
+	}
+	c.Header("Content-Disposition", `attachment; filename="interview.ics"`)
+	c.Header("Cache-Control", "private, no-store")
+	c.Data(http.StatusOK, ical.ContentType, helpers.InterviewInvite(interview, ""))
+}
+
+// preloadInterview loads everything invites and notifications need
+func preloadInterview(db *gorm.DB) *gorm.DB {
+	return db.Preload("Slots", orderByStart).Preload("Interviewers").
+		Preload("Application.User").Preload("Application.Job.Company")
+}
+
+func orderByStart(db *gorm.DB) *gorm.DB {
+	return db.Order("starts_at, id")
+}
+
+// companyInterview loads the interview named by the id parameter if it
+// belongs to one of the company's applications. It writes the error
+// response itself and reports false otherwise.
+func companyInterview(c *gin.Context, companyID uint) (model.Interview, bool) {
+	var interview model.Interview
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid interview ID"})
+		return interview, false
+	}
+	if err := preloadInterview(initializer.DB).
+		Joins("JOIN applications ON applications.id = interviews.application_id").
+		Joins("JOIN jobs ON jobs.id = applications.job_id").
+		Where("jobs.company_id = ?", companyID).
+		First(&interview, id).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Interview not found"})
+		return interview, false
+	}
+	return interview, true
+}
+
+// userInterview loads the interview named by the id parameter if it belongs
+// to one of the user's applications
+func userInterview(c *gin.Context, userID uint) (model.Interview, bool) {
+	var interview model.Interview
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid interview ID"})
+		return interview, false
+	}
+	if err := preloadInterview(initializer.DB).
+		Joins("JOIN applications ON applications.id = interviews.application_id").
+		Where("applications.user_id = ?", userID).
+		First(&interview, id).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Interview not found"})
+		return interview, false
+	}
+	return interview, true
+}
+
+// interviewUserID returns the ID of the authenticated user
+func interviewUserID(c *gin.Context) (uint, bool) {
+	user, ok := c.Get("user")
+	if !ok || user == nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not found"})
+		return 0, false
+	}
+	userModel, ok := user.(model.User)
+	if !ok || userModel.ID == 0 {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid user ID"})
+		return 0, false
+	}
+	return userModel.ID, true
+}
+
+// uniqueIDs drops repeated IDs
+func uniqueIDs(ids []uint) []uint {
+	seen := map[uint]bool{}
+	unique := ids[:0:0]
+	for _, id := range ids {
+		if !seen[id] {
+			seen[id] = true
+			unique = append(unique, id)
+		}
+	}
+	return unique
+}
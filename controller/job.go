@@ -1,12 +1,17 @@
 package controller
 
 import (
+	"errors"
 	"net/http"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/sahilq312/workly/helpers"
 	"github.com/sahilq312/workly/initializer"
 	"github.com/sahilq312/workly/model"
+	"gorm.io/gorm"
 )
 
 // CreateJob creates a new job
@@ -25,100 +30,235 @@ func CreateJob(c *gin.Context) {
 
 	// Bind request body
 	var body struct {
-		Title       string   `json:"title"`
-		Description string   `json:"description"`
-		Location    string   `json:"location"`
-		Salary      string   `json:"salary"`
-		Skills      []string `json:"skills"` // Skill names
+		Title           string                  `json:"title"`
+		Description     string                  `json:"description"`
+		Location        string                  `json:"location"`
+		Locations       []helpers.LocationInput `json:"locations"`
+		WorkMode        string                  `json:"work_mode"` // onsite (default), hybrid or remote
+		RemoteCountries []string                `json:"remote_countries"`
+		Salary          string                  `json:"salary"`
+		Compensation    *model.Compensation     `json:"compensation"`
+		Skills          []string                `json:"skills"`   // Skill names
+		Category        string                  `json:"category"` // Category slug
+		Seniority       string                  `json:"seniority"`
+		EmploymentType  string                  `json:"employment_type"`
+		Status          string                  `json:"status"` // draft (default) or published
+		ExpiresAt       *time.Time              `json:"expires_at"`
+		Questions       []helpers.QuestionInput `json:"questions"` // Screening questions
 	}
 	if err := c.BindJSON(&body); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
 		return
 	}
 
+	if body.Status == "" {
+		body.Status = model.JobDraft
+	}
+	if body.Status != model.JobDraft && body.Status != model.JobPublished {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "New jobs must be draft or published"})
+		return
+	}
+	if body.ExpiresAt != nil && !body.ExpiresAt.After(time.Now()) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "expires_at must be in the future"})
+		return
+	}
+
 	// Validate required fields
-	if body.Title == "" || body.Description == "" || body.Location == "" || body.Salary == "" || len(body.Skills) == 0 {
+	if body.Title == "" || body.Description == "" || (body.Salary == "" && body.Compensation == nil) || len(body.Skills) == 0 {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "All fields are required"})
 		return
 	}
 
-	// Fetch or create skills
-	var skills []model.Skill
-	for _, skillName := range body.Skills {
-		var skill model.Skill
-		if err := initializer.DB.FirstOrCreate(&skill, model.Skill{Name: skillName}).Error; err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Error processing skills"})
-			return
-		}
-		skills = append(skills, skill)
+	placement, err := helpers.ResolveJobPlacement(body.WorkMode, body.Locations, body.Location, body.RemoteCountries)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	compensation, err := resolveCompensation(body.Compensation, body.Salary)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	categoryID, err := resolveJobClassification(body.Category, body.Seniority, body.EmploymentType)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	questions, err := helpers.BuildScreeningQuestions(body.Questions)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Map skill names to canonical skills, creating unknown ones
+	skills, err := helpers.ResolveSkills(initializer.DB, body.Skills)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error processing skills"})
+		return
 	}
 
 	// Create the job
 	job := model.Job{
-		Title:       body.Title,
-		Description: body.Description,
-		Location:    body.Location,
-		Salary:      body.Salary,
-		CompanyID:   companyModel.ID,
-		Skills:      skills,
+		Title:           body.Title,
+		Description:     body.Description,
+		Location:        placement.Label,
+		Locations:       placement.Locations,
+		WorkMode:        placement.WorkMode,
+		RemoteCountries: placement.RemoteCountries,
+		Salary:          body.Salary,
+		Compensation:    compensation,
+		CategoryID:      categoryID,
+		Seniority:       body.Seniority,
+		EmploymentType:  body.EmploymentType,
+		CompanyID:       companyModel.ID,
+		Skills:          skills,
+		Status:          body.Status,
+		ExpiresAt:       body.ExpiresAt,
+		Questions:       questions,
+	}
+	if job.Status == model.JobPublished {
+		now := time.Now()
+		job.PublishedAt = &now
 	}
 	if err := initializer.DB.Create(&job).Error; err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create job"})
 		return
 	}
 
+	helpers.RecordAudit(c, helpers.AuditEntry{
+		Action:     model.AuditJobCreate,
+		EntityType: "job",
+		EntityID:   job.ID,
+		After:      job,
+	})
+
 	// Return the created job
 	c.JSON(http.StatusCreated, gin.H{"data": job})
 }
 
 // GetJob retrieves a job by ID
 func GetJob(c *gin.Context) {
-	id := c.Param("id")
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Job not found"})
+		return
+	}
 	var job model.Job
-	result := initializer.DB.Preload("Skills").First(&job, id)
+	result := initializer.Reader(c.Request.Context()).Preload("Company").Preload("Skills").Preload("Locations").Preload("Category").
+		Preload("Questions", orderByPosition).Preload("Questions.Options", orderByPosition).
+		Where("status NOT IN ?", []string{model.JobDraft, model.JobPaused}).
+		First(&job, id)
 	if result.Error != nil {
 		c.JSON(http.StatusNotFound, gin.H{"error": "Job not found"})
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"job": job})
+	job.Redact()
+	c.JSON(http.StatusOK, gin.H{"job": job, "url": helpers.JobURL(job)})
 }
 
 // UpdateJob updates an existing job
 func UpdateJob(c *gin.Context) {
+	company, ok := c.Get("company")
+	if !ok || company == nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Company not found"})
+		return
+	}
+	companyModel, ok := company.(model.Company)
+	if !ok || companyModel.ID == 0 {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized access"})
+		return
+	}
+
 	var body struct {
-		Title       string   `json:"title"`
-		Description string   `json:"description"`
-		Location    string   `json:"location"`
-		Salary      string   `json:"salary"`
-		CompanyID   uint     `json:"company_id"`
-		Skills      []string `json:"skills"` // Skill names
+		Title           string                  `json:"title"`
+		Description     string                  `json:"description"`
+		Location        string                  `json:"location"`
+		Locations       []helpers.LocationInput `json:"locations"`
+		WorkMode        string                  `json:"work_mode"`
+		RemoteCountries []string                `json:"remote_countries"`
+		Salary          string                  `json:"salary"`
+		Compensation    *model.Compensation     `json:"compensation"`
+		CompanyID       uint                    `json:"company_id"`
+		Skills          []string                `json:"skills"` // Skill names
+		Category        string                  `json:"category"`
+		Seniority       string                  `json:"seniority"`
+		EmploymentType  string                  `json:"employment_type"`
+		ExpiresAt       *time.Time              `json:"expires_at"`
+		// Replaces the screening questions when present; omit to keep them
+		Questions *[]helpers.QuestionInput `json:"questions"`
 	}
 	if err := c.BindJSON(&body); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
 		return
 	}
 
-	jobID := c.Param("id")
+	jobID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid job ID"})
+		return
+	}
 	var job model.Job
-	result := initializer.DB.First(&job, jobID)
+	result := initializer.DB.Preload("Skills").Preload("Locations").Where("company_id = ?", companyModel.ID).First(&job, jobID)
 	if result.Error != nil {
 		c.JSON(http.StatusNotFound, gin.H{"error": "Job not found"})
 		return
 	}
+	before := job
 
 	// Update job fields
 	job.Title = body.Title
 	job.Description = body.Description
-	job.Location = body.Location
+	placement, err := helpers.ResolveJobPlacement(body.WorkMode, body.Locations, body.Location, body.RemoteCountries)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	job.Location = placement.Label
+	job.WorkMode = placement.WorkMode
+	job.RemoteCountries = placement.RemoteCountries
+	job.Locations = nil
+	if body.Compensation != nil || body.Salary != job.Salary {
+		compensation, err := resolveCompensation(body.Compensation, body.Salary)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		job.Compensation = compensation
+	}
 	job.Salary = body.Salary
+	categoryID, err := resolveJobClassification(body.Category, body.Seniority, body.EmploymentType)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	job.CategoryID = categoryID
+	job.Seniority = body.Seniority
+	job.EmploymentType = body.EmploymentType
+	if body.ExpiresAt != nil {
+		if !body.ExpiresAt.After(time.Now()) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "expires_at must be in the future"})
+			return
+		}
+		job.ExpiresAt = body.ExpiresAt
+	}
+
+	var questions []model.ScreeningQuestion
+	if body.Questions != nil {
+		if questions, err = helpers.BuildScreeningQuestions(*body.Questions); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
 
 	// Update skills
-	var skills []model.Skill
-	for _, skillName := range body.Skills {
-		var skill model.Skill
-		initializer.DB.FirstOrCreate(&skill, model.Skill{Name: skillName})
-		skills = append(skills, skill)
+	skills, err := helpers.ResolveSkills(initializer.DB, body.Skills)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error processing skills"})
+		return
 	}
 	job.Skills = skills
 
@@ -126,23 +266,132 @@ func UpdateJob(c *gin.Context) {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update job"})
 		return
 	}
+	if err := initializer.DB.Model(&job).Association("Skills").Replace(skills); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update job skills"})
+		return
+	}
+	if err := replaceJobLocations(initializer.DB, &job, placement.Locations); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update job locations"})
+		return
+	}
+	if body.Questions != nil {
+		if err := replaceScreeningQuestions(initializer.DB, &job, questions); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update screening questions"})
+			return
+		}
+	}
+
+	helpers.RecordAudit(c, helpers.AuditEntry{
+		Action:     model.AuditJobUpdate,
+		EntityType: "job",
+		EntityID:   job.ID,
+		Before:     before,
+		After:      job,
+	})
 
 	c.JSON(http.StatusOK, gin.H{"message": "Job updated successfully"})
 }
 
+// TransitionJob returns a handler that moves one of the company's jobs to the
+// given state. When from is set the job must currently be in one of those states.
+func TransitionJob(to string, from ...string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		company, ok := c.Get("company")
+		if !ok || company == nil {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Company not found"})
+			return
+		}
+		companyModel, ok := company.(model.Company)
+		if !ok || companyModel.ID == 0 {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized access"})
+			return
+		}
+
+		// Optional new expiry date when publishing or reopening
+		var body struct {
+			ExpiresAt *time.Time `json:"expires_at"`
+		}
+		if c.Request.ContentLength > 0 {
+			if err := c.ShouldBindJSON(&body); err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+				return
+			}
+		}
+
+		id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid job ID"})
+			return
+		}
+		var job model.Job
+		if err := initializer.DB.Where("company_id = ?", companyModel.ID).First(&job, id).Error; err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Job not found"})
+			return
+		}
+		if !model.CanTransitionJob(job.Status, to) || len(from) > 0 && !helpers.ContainsString(from, job.Status) {
+			c.JSON(http.StatusConflict, gin.H{"error": "Cannot move a " + job.Status + " job to " + to})
+			return
+		}
+
+		now := time.Now()
+		previousStatus := job.Status
+		if to == model.JobPublished {
+			if body.ExpiresAt != nil {
+				job.ExpiresAt = body.ExpiresAt
+			}
+			if job.ExpiresAt != nil && !job.ExpiresAt.After(now) {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "Set a future expires_at before publishing this job"})
+				return
+			}
+			if job.PublishedAt == nil || previousStatus == model.JobClosed || previousStatus == model.JobExpired {
+				job.PublishedAt = &now
+			}
+		}
+		job.Status = to
+
+		if err := initializer.DB.Model(&job).Select("Status", "PublishedAt", "ExpiresAt").Updates(&job).Error; err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update job status"})
+			return
+		}
+
+		helpers.RecordAudit(c, helpers.AuditEntry{
+			Action:     model.AuditJobStatus,
+			EntityType: "job",
+			EntityID:   job.ID,
+			Before:     gin.H{"status": previousStatus},
+			After:      gin.H{"status": to},
+		})
+
+		c.JSON(http.StatusOK, gin.H{"message": "Job is now " + to, "job": job})
+	}
+}
+
 // DeleteJob deletes a job by ID
 func DeleteJob(c *gin.Context) {
-	company, _ := c.Get("company")
-	id := c.Param("id")
+	company, ok := c.Get("company")
+	if !ok || company == nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Company not found"})
+		return
+	}
+	companyModel, ok := company.(model.Company)
+	if !ok || companyModel.ID == 0 {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized access"})
+		return
+	}
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid job ID"})
+		return
+	}
 
-	valid := initializer.DB.Model(&model.Job{}).Where("id = ? AND company_id = ?", id, company).First(&model.Job{}).Error
+	valid := initializer.DB.Model(&model.Job{}).Where("id = ? AND company_id = ?", id, companyModel.ID).First(&model.Job{}).Error
 	if valid != nil {
 		c.JSON(http.StatusUnauthorized, gin.H{"error": "You are not authorized to delete this job"})
 		return
 	}
 
 	var job model.Job
-	result := initializer.DB.First(&job, id)
+	result := initializer.DB.Preload("Skills").First(&job, id)
 	if result.Error != nil {
 		c.JSON(http.StatusNotFound, gin.H{"error": "Job not found"})
 		return
@@ -153,41 +402,71 @@ func DeleteJob(c *gin.Context) {
 		return
 	}
 
+	helpers.RecordAudit(c, helpers.AuditEntry{
+		Action:     model.AuditJobDelete,
+		EntityType: "job",
+		EntityID:   job.ID,
+		Before:     job,
+	})
+
 	c.JSON(http.StatusOK, gin.H{"message": "Job deleted successfully"})
 }
 
 // GetAllJobs retrieves all jobs
 func GetAllJobs(c *gin.Context) {
-	page := 1
-	perPage := 10
-	pageStr := c.Query("page")
+	// Get filtering, search and sort parameters
+	filter, err := helpers.ParseJobFilter(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
 
-	// Get filtering and search parameters
-	title := c.Query("title")
-	location := c.Query("location")
-	//skills := c.QueryArray("skills") // skills provided as an array of skill IDs
-	search := c.Query("search")
+	listing, ok := listJobs(c, filter)
+	if !ok {
+		return
+	}
+	c.JSON(http.StatusOK, listing)
+}
 
-	// Parse page number if provided
-	if pageStr != "" {
-		page, _ = strconv.Atoi(pageStr)
+// SearchJobs retrieves jobs like GetAllJobs and adds facet counts for each filter dimension
+func SearchJobs(c *gin.Context) {
+	filter, err := helpers.ParseJobFilter(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
 	}
 
-	// Initialize query on Job model
-	query := initializer.DB.Model(&model.Job{})
+	listing, ok := listJobs(c, filter)
+	if !ok {
+		return
+	}
 
-	// Apply filters if provided
-	if title != "" {
-		query = query.Where("title ILIKE ?", "%"+title+"%")
+	facets, err := filter.Facets(initializer.Reader(c.Request.Context()))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to count facets"})
+		return
 	}
-	if location != "" {
-		query = query.Where("location ILIKE ?", "%"+location+"%")
+	listing["facets"] = facets
+	c.JSON(http.StatusOK, listing)
+}
+
+// listJobs runs a paginated job query and builds the listing response.
+// It writes the error response itself and reports false on failure.
+func listJobs(c *gin.Context, filter helpers.JobFilter) (gin.H, bool) {
+	page := 1
+	perPage := 10
+
+	// Parse page number if provided
+	if pageStr := c.Query("page"); pageStr != "" {
+		page, _ = strconv.Atoi(pageStr)
 	}
-	if search != "" {
-		// Apply search across title and description fields
-		query = query.Where("title ILIKE ? OR description ILIKE ?", "%"+search+"%", "%"+search+"%")
+	if page < 1 {
+		page = 1
 	}
 
+	// Initialize query on Job model and apply filters
+	query := filter.Apply(initializer.Reader(c.Request.Context()).Model(&model.Job{}))
+
 	// Count total rows after applying filters
 	var totalRows int64
 	query.Count(&totalRows)
@@ -196,21 +475,35 @@ func GetAllJobs(c *gin.Context) {
 	// Retrieve the jobs with pagination
 	offset := (page - 1) * perPage
 	var jobs []model.Job
-	result := query.Offset(offset).Limit(perPage).Find(&jobs)
+	result := filter.Order(query).Preload("Skills").Preload("Locations").Preload("Category").Offset(offset).Limit(perPage).Find(&jobs)
 	if result.Error != nil {
 		c.JSON(http.StatusBadGateway, gin.H{
 			"error": "No response",
 		})
-		return
+		return nil, false
+	}
+
+	ids := make([]uint, len(jobs))
+	for i := range jobs {
+		jobs[i].Redact()
+		ids[i] = jobs[i].ID
+	}
+
+	// Highlighted snippets for search results, keyed by job ID
+	highlights, err := filter.Highlights(initializer.Reader(c.Request.Context()), ids)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to highlight search results"})
+		return nil, false
 	}
 
-	// Return paginated and filtered results
-	c.JSON(http.StatusOK, gin.H{
+	// Paginated and filtered results
+	return gin.H{
 		"jobs":       jobs,
+		"highlights": highlights,
 		"totalPages": totalPages,
 		"page":       page,
 		"totalRows":  totalRows,
-	})
+	}, true
 }
 
 // GetJobsByCompany retrieves jobs by company ID
@@ -221,53 +514,80 @@ func GetJobsByCompany(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{"jobs": jobs})
 }
 
-// GetJobsByLocation retrieves jobs by location
-func GetJobsByLocation(c *gin.Context) {
-	var body struct {
-		Location string `json:"location"`
-	}
-	if err := c.BindJSON(&body); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
-		return
-	}
+// replaceJobLocations swaps the stored locations of a job for a new set
+func replaceJobLocations(db *gorm.DB, job *model.Job, locations []model.JobLocation) error {
+	return db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Unscoped().Where("job_id = ?", job.ID).Delete(&model.JobLocation{}).Error; err != nil {
+			return err
+		}
+		for i := range locations {
+			locations[i].JobID = job.ID
+		}
+		if len(locations) > 0 {
+			if err := tx.Create(&locations).Error; err != nil {
+				return err
+			}
+		}
+		job.Locations = locations
+		return nil
+	})
+}
 
-	var jobs []model.Job
-	initializer.DB.Preload("Skills").Where("location = ?", body.Location).Find(&jobs)
-	if len(jobs) == 0 {
-		c.JSON(http.StatusNotFound, gin.H{"error": "No jobs found"})
-		return
+// replaceScreeningQuestions swaps the questions of a job for a new set. Old
+// questions are soft-deleted; answers already given keep their own copy of
+// the prompt.
+func replaceScreeningQuestions(db *gorm.DB, job *model.Job, questions []model.ScreeningQuestion) error {
+	return db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("job_id = ?", job.ID).Delete(&model.ScreeningQuestion{}).Error; err != nil {
+			return err
+		}
+		for i := range questions {
+			questions[i].JobID = job.ID
+		}
+		if len(questions) > 0 {
+			if err := tx.Create(&questions).Error; err != nil {
+				return err
+			}
+		}
+		job.Questions = questions
+		return nil
+	})
+}
+
+// resolveCompensation validates a structured pay range, or falls back to a
+// best-effort parse of the free-form salary text
+func resolveCompensation(compensation *model.Compensation, salary string) (model.Compensation, error) {
+	if compensation != nil {
+		comp := *compensation
+		if err := helpers.ValidateCompensation(&comp); err != nil {
+			return model.Compensation{}, err
+		}
+		return comp, nil
 	}
-	c.JSON(http.StatusOK, gin.H{"jobs": jobs})
+	comp, _ := helpers.ParseSalary(salary)
+	return comp, nil
 }
 
-// GetJobsBySkill retrieves jobs by skill
-func GetJobsBySkill(c *gin.Context) {
-	var body struct {
-		Skill string `json:"skill"`
+// resolveJobClassification checks seniority and employment type, which may be
+// empty, and looks up the category by slug
+func resolveJobClassification(category, seniority, employmentType string) (*uint, error) {
+	if seniority != "" && !helpers.ContainsString(model.SeniorityLevels, seniority) {
+		return nil, errors.New("seniority must be one of " + strings.Join(model.SeniorityLevels, ", "))
 	}
-	if err := c.BindJSON(&body); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
-		return
+	if employmentType != "" && !helpers.ContainsString(model.EmploymentTypes, employmentType) {
+		return nil, errors.New("employment_type must be one of " + strings.Join(model.EmploymentTypes, ", "))
 	}
-
-	var jobs []model.Job
-	// Join with skills table and filter jobs by the specified skill name
-	err := initializer.DB.
-		Joins("JOIN job_skills ON job_skills.job_id = jobs.id").
-		Joins("JOIN skills ON skills.id = job_skills.skill_id").
-		Where("skills.name = ?", body.Skill).
-		Preload("Skills").
-		Find(&jobs).Error
-
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve jobs"})
-		return
+	if category == "" {
+		return nil, nil
 	}
-
-	if len(jobs) == 0 {
-		c.JSON(http.StatusNotFound, gin.H{"error": "No jobs found for the specified skill"})
-		return
+	var jobCategory model.JobCategory
+	if err := initializer.DB.Where("slug = ?", category).First(&jobCategory).Error; err != nil {
+		return nil, errors.New("unknown category: " + category)
 	}
+	return &jobCategory.ID, nil
+}
 
-	c.JSON(http.StatusOK, gin.H{"jobs": jobs})
+// orderByPosition sorts preloaded screening questions and options
+func orderByPosition(db *gorm.DB) *gorm.DB {
+	return db.Order("position, id")
 }
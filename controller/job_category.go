@@ -0,0 +1,164 @@
+package controller
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sahilq312/workly/helpers"
+	"github.com/sahilq312/workly/initializer"
+	"github.com/sahilq312/workly/model"
+	"github.com/sahilq312/workly/utils"
+	"gorm.io/gorm"
+)
+
+// CategoryCount is a job category with its number of open jobs
+type CategoryCount struct {
+	model.JobCategory
+	Jobs int64 `json:"jobs"`
+}
+
+// GetJobCategories lists job categories with the number of open jobs in each,
+// along with open job counts per seniority level and employment type, for
+// browsing pages
+func GetJobCategories(c *gin.Context) {
+	db := initializer.Reader(c.Request.Context())
+	openJobs := func() *gorm.DB {
+		return helpers.JobFilter{}.Apply(db.Table("jobs")).Where("jobs.deleted_at IS NULL")
+	}
+
+	var categories []CategoryCount
+	err := db.Table("job_categories").
+		Select("job_categories.*, COUNT(open_jobs.id) AS jobs").
+		Joins("LEFT JOIN (?) AS open_jobs ON open_jobs.category_id = job_categories.id", openJobs().Select("jobs.id, jobs.category_id")).
+		Where("job_categories.deleted_at IS NULL").
+		Group("job_categories.id").
+		Order("job_categories.name").
+		Scan(&categories).Error
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch categories"})
+		return
+	}
+
+	var seniority, employmentTypes []helpers.FacetCount
+	err = openJobs().Select("jobs.seniority AS value, COUNT(*) AS count").Group("jobs.seniority").Scan(&seniority).Error
+	if err == nil {
+		err = openJobs().Select("jobs.employment_type AS value, COUNT(*) AS count").Group("jobs.employment_type").Scan(&employmentTypes).Error
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch categories"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"categories":       categories,
+		"seniority":        countsFor(model.SeniorityLevels, seniority),
+		"employment_types": countsFor(model.EmploymentTypes, employmentTypes),
+	})
+}
+
+// countsFor lists every value in order with its count, including zeros
+func countsFor(values []string, facets []helpers.FacetCount) []helpers.FacetCount {
+	counts := map[string]int64{}
+	for _, facet := range facets {
+		counts[facet.Value] = facet.Count
+	}
+	result := make([]helpers.FacetCount, len(values))
+	for i, value := range values {
+		result[i] = helpers.FacetCount{Value: value, Count: counts[value]}
+	}
+	return result
+}
+
+// CreateJobCategory adds a job category. The slug defaults to one derived from the name.
+func CreateJobCategory(c *gin.Context) {
+	var body struct {
+		Name        string `json:"name"`
+		Slug        string `json:"slug"`
+		Description string `json:"description"`
+	}
+	if err := c.ShouldBindJSON(&body); err != nil || strings.TrimSpace(body.Name) == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Name is required"})
+		return
+	}
+	if body.Slug == "" {
+		body.Slug = body.Name
+	}
+	category := model.JobCategory{
+		Name:        strings.TrimSpace(body.Name),
+		Slug:        utils.Slugify(body.Slug),
+		Description: body.Description,
+	}
+	if category.Slug == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Slug must contain letters or digits"})
+		return
+	}
+	if err := initializer.DB.Create(&category).Error; err != nil {
+		c.JSON(http.StatusConflict, gin.H{"error": "A category with this name or slug already exists"})
+		return
+	}
+	c.JSON(http.StatusCreated, gin.H{"data": category})
+}
+
+// UpdateJobCategory renames a job category or changes its slug or description
+func UpdateJobCategory(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid category ID"})
+		return
+	}
+	var category model.JobCategory
+	if err := initializer.DB.First(&category, id).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Category not found"})
+		return
+	}
+	var body struct {
+		Name        *string `json:"name"`
+		Slug        *string `json:"slug"`
+		Description *string `json:"description"`
+	}
+	if err := c.ShouldBindJSON(&body); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+	if body.Name != nil && strings.TrimSpace(*body.Name) != "" {
+		category.Name = strings.TrimSpace(*body.Name)
+	}
+	if body.Slug != nil {
+		if category.Slug = utils.Slugify(*body.Slug); category.Slug == "" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Slug must contain letters or digits"})
+			return
+		}
+	}
+	if body.Description != nil {
+		category.Description = *body.Description
+	}
+	if err := initializer.DB.Save(&category).Error; err != nil {
+		c.JSON(http.StatusConflict, gin.H{"error": "A category with this name or slug already exists"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": category})
+}
+
+// DeleteJobCategory removes a job category; its jobs become uncategorized
+func DeleteJobCategory(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid category ID"})
+		return
+	}
+	var category model.JobCategory
+	if err := initializer.DB.First(&category, id).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Category not found"})
+		return
+	}
+	if err := initializer.DB.Model(&model.Job{}).Where("category_id = ?", category.ID).Update("category_id", nil).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete category"})
+		return
+	}
+	if err := initializer.DB.Unscoped().Delete(&category).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete category"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Category deleted successfully"})
+}
@@ -0,0 +1,413 @@
+package controller
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sahilq312/workly/helpers"
+	"github.com/sahilq312/workly/initializer"
+	"github.com/sahilq312/workly/model"
+	"gorm.io/gorm"
+)
+
+// Limits for a single bulk import
+const (
+	maxImportBytes = 5 << 20
+	maxImportRows  = 1000
+)
+
+// Import row outcomes
+const (
+	importCreate = "create"
+	importUpdate = "update"
+)
+
+// ImportRowResult reports what happened, or would happen in a dry run, to one row
+type ImportRowResult struct {
+	Line        int      `json:"line"`
+	ExternalRef string   `json:"external_ref,omitempty"`
+	Action      string   `json:"action,omitempty"`
+	JobID       uint     `json:"job_id,omitempty"`
+	Errors      []string `json:"errors,omitempty"`
+}
+
+// plannedImport is a validated row ready to be written
+type plannedImport struct {
+	result    *ImportRowResult
+	job       model.Job
+	before    model.Job
+	skills    []string
+	locations []model.JobLocation
+}
+
+// ImportJobs creates or updates many of the company's jobs from a CSV or JSON
+// file. Rows carrying an external_ref (or id) update the matching job, so
+// repeated syncs are idempotent. Nothing is written unless every row is
+// valid; dry_run=true only reports what would happen.
+//
+// The file is the request body (Content-Type text/csv or application/json) or
+// a multipart "file" field; ?format=csv|json overrides detection.
+func ImportJobs(c *gin.Context) {
+	company, ok := c.Get("company")
+	if !ok || company == nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Company not found"})
+		return
+	}
+	companyModel, ok := company.(model.Company)
+	if !ok || companyModel.ID == 0 {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized access"})
+		return
+	}
+	dryRun, _ := strconv.ParseBool(c.Query("dry_run"))
+
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxImportBytes)
+	body, format, err := importFile(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	parsed, err := helpers.ParseJobImport(body, format)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if len(parsed) == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "The file contains no jobs"})
+		return
+	}
+	if len(parsed) > maxImportRows {
+		c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": fmt.Sprintf("At most %d jobs can be imported at once", maxImportRows)})
+		return
+	}
+
+	existingByRef, existingByID, err := loadImportTargets(companyModel.ID, parsed)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load existing jobs"})
+		return
+	}
+
+	now := time.Now()
+	results := make([]ImportRowResult, len(parsed))
+	plans := make([]plannedImport, 0, len(parsed))
+	seenRefs := map[string]int{}
+	seenIDs := map[uint]int{}
+	invalid := 0
+	for i, row := range parsed {
+		result := &results[i]
+		result.Line, result.ExternalRef, result.Errors = row.Line, row.Row.ExternalRef, row.Errors
+
+		var existing *model.Job
+		if ref := row.Row.ExternalRef; ref != "" {
+			if line, dup := seenRefs[ref]; dup {
+				result.Errors = append(result.Errors, fmt.Sprintf("external_ref %q already used on line %d", ref, line))
+			}
+			seenRefs[ref] = row.Line
+			if job, ok := existingByRef[ref]; ok {
+				existing = &job
+			}
+		} else if row.Row.ID != 0 {
+			if line, dup := seenIDs[row.Row.ID]; dup {
+				result.Errors = append(result.Errors, fmt.Sprintf("job %d already updated on line %d", row.Row.ID, line))
+			}
+			seenIDs[row.Row.ID] = row.Line
+			job, ok := existingByID[row.Row.ID]
+			if !ok {
+				result.Errors = append(result.Errors, fmt.Sprintf("job %d not found", row.Row.ID))
+			} else {
+				existing = &job
+			}
+		}
+
+		plan, errs := planImportRow(row.Row, existing, companyModel.ID, now)
+		result.Errors = append(result.Errors, errs...)
+		if len(result.Errors) > 0 {
+			invalid++
+			continue
+		}
+		plan.result = result
+		result.Action, result.JobID = importCreate, plan.job.ID
+		if existing != nil {
+			result.Action = importUpdate
+		}
+		plans = append(plans, plan)
+	}
+
+	summary := gin.H{"rows": len(results), "invalid": invalid, "create": 0, "update": 0}
+	for _, plan := range plans {
+		summary[plan.result.Action] = summary[plan.result.Action].(int) + 1
+	}
+	if invalid > 0 {
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"dry_run": dryRun, "summary": summary, "results": results})
+		return
+	}
+	if dryRun {
+		c.JSON(http.StatusOK, gin.H{"dry_run": true, "summary": summary, "results": results})
+		return
+	}
+
+	err = initializer.DB.Transaction(func(tx *gorm.DB) error {
+		for i := range plans {
+			if err := writeImportPlan(tx, &plans[i]); err != nil {
+				return fmt.Errorf("line %d: %w", plans[i].result.Line, err)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Import failed, no jobs were changed"})
+		return
+	}
+
+	for _, plan := range plans {
+		entry := helpers.AuditEntry{EntityType: "job", EntityID: plan.job.ID, After: plan.job}
+		if plan.result.Action == importCreate {
+			entry.Action = model.AuditJobCreate
+		} else {
+			entry.Action, entry.Before = model.AuditJobUpdate, plan.before
+		}
+		helpers.RecordAudit(c, entry)
+	}
+	c.JSON(http.StatusOK, gin.H{"dry_run": false, "summary": summary, "results": results})
+}
+
+// ExportJobs downloads all of the company's jobs, in any state, as CSV or
+// JSON (?format=json) in the import format
+func ExportJobs(c *gin.Context) {
+	company, ok := c.Get("company")
+	if !ok || company == nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Company not found"})
+		return
+	}
+	companyModel, ok := company.(model.Company)
+	if !ok || companyModel.ID == 0 {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized access"})
+		return
+	}
+	format := strings.ToLower(c.DefaultQuery("format", helpers.FormatCSV))
+	if format != helpers.FormatCSV && format != helpers.FormatJSON {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "format must be csv or json"})
+		return
+	}
+
+	var jobs []model.Job
+	if err := initializer.Reader(c.Request.Context()).
+		Preload("Skills").Preload("Locations").Preload("Category").
+		Where("company_id = ?", companyModel.ID).
+		Order("id").Find(&jobs).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch jobs"})
+		return
+	}
+
+	filename := fmt.Sprintf("jobs-%s.%s", time.Now().UTC().Format("20060102"), format)
+	c.Header("Content-Disposition", `attachment; filename="`+filename+`"`)
+	if format == helpers.FormatJSON {
+		rows := make([]helpers.JobImportRow, len(jobs))
+		for i, job := range jobs {
+			rows[i] = helpers.JobExportRow(job)
+		}
+		c.JSON(http.StatusOK, gin.H{"jobs": rows})
+		return
+	}
+
+	var buf bytes.Buffer
+	if err := helpers.WriteJobsCSV(&buf, jobs); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to export jobs"})
+		return
+	}
+	c.Data(http.StatusOK, "text/csv; charset=utf-8", buf.Bytes())
+}
+
+// importFile returns the uploaded file and its format
+func importFile(c *gin.Context) (io.Reader, string, error) {
+	format := strings.ToLower(c.Query("format"))
+	var body io.Reader = c.Request.Body
+
+	if strings.HasPrefix(c.ContentType(), "multipart/") {
+		header, err := c.FormFile("file")
+		if err != nil {
+			return nil, "", errors.New("multipart uploads need a file field")
+		}
+		file, err := header.Open()
+		if err != nil {
+			return nil, "", err
+		}
+		data, err := io.ReadAll(file)
+		file.Close()
+		if err != nil {
+			return nil, "", err
+		}
+		body = bytes.NewReader(data)
+		if format == "" {
+			format = strings.TrimPrefix(strings.ToLower(filepath.Ext(header.Filename)), ".")
+		}
+	}
+
+	if format == "" {
+		switch c.ContentType() {
+		case "text/csv", "application/csv":
+			format = helpers.FormatCSV
+		case "application/json":
+			format = helpers.FormatJSON
+		}
+	}
+	if format != helpers.FormatCSV && format != helpers.FormatJSON {
+		return nil, "", errors.New("send CSV or JSON, or set format=csv|json")
+	}
+	return body, format, nil
+}
+
+// loadImportTargets fetches the company's jobs referenced by the import rows
+func loadImportTargets(companyID uint, rows []helpers.ParsedImportRow) (map[string]model.Job, map[uint]model.Job, error) {
+	var refs []string
+	var ids []uint
+	for _, row := range rows {
+		if row.Row.ExternalRef != "" {
+			refs = append(refs, row.Row.ExternalRef)
+		} else if row.Row.ID != 0 {
+			ids = append(ids, row.Row.ID)
+		}
+	}
+
+	byRef := map[string]model.Job{}
+	byID := map[uint]model.Job{}
+	if len(refs) == 0 && len(ids) == 0 {
+		return byRef, byID, nil
+	}
+	var jobs []model.Job
+	err := initializer.DB.Preload("Skills").Preload("Locations").
+		Where("company_id = ?", companyID).
+		Where("external_ref IN ? OR id IN ?", append(refs, ""), append(ids, 0)).
+		Find(&jobs).Error
+	if err != nil {
+		return nil, nil, err
+	}
+	for _, job := range jobs {
+		if job.ExternalRef != "" {
+			byRef[job.ExternalRef] = job
+		}
+		byID[job.ID] = job
+	}
+	return byRef, byID, nil
+}
+
+// planImportRow validates a row the same way CreateJob and UpdateJob do and
+// builds the job it would produce, without writing anything
+func planImportRow(row helpers.JobImportRow, existing *model.Job, companyID uint, now time.Time) (plannedImport, []string) {
+	var errs []string
+	fail := func(err error) {
+		errs = append(errs, err.Error())
+	}
+
+	job := model.Job{CompanyID: companyID, Status: model.JobDraft}
+	var before model.Job
+	if existing != nil {
+		job, before = *existing, *existing
+	}
+
+	if row.Title == "" {
+		fail(errors.New("title is required"))
+	}
+	if row.Description == "" {
+		fail(errors.New("description is required"))
+	}
+	if row.Salary == "" && row.Compensation == nil {
+		fail(errors.New("salary or compensation is required"))
+	}
+	if len(row.Skills) == 0 {
+		fail(errors.New("at least one skill is required"))
+	}
+
+	placement, err := helpers.ResolveJobPlacement(row.WorkMode, row.Locations, row.Location, row.RemoteCountries)
+	if err != nil {
+		fail(err)
+	}
+	compensation, err := resolveCompensation(row.Compensation, row.Salary)
+	if err != nil {
+		fail(err)
+	}
+	categoryID, err := resolveJobClassification(row.Category, row.Seniority, row.EmploymentType)
+	if err != nil {
+		fail(err)
+	}
+
+	if row.ExpiresAt != nil && !row.ExpiresAt.After(now) &&
+		(existing == nil || existing.ExpiresAt == nil || !existing.ExpiresAt.Equal(*row.ExpiresAt)) {
+		fail(errors.New("expires_at must be in the future"))
+	}
+
+	status := row.Status
+	switch {
+	case existing == nil && status == "":
+		status = model.JobDraft
+	case existing == nil && status != model.JobDraft && status != model.JobPublished:
+		fail(errors.New("new jobs must be draft or published"))
+	case existing != nil && status == "":
+		status = existing.Status
+	case existing != nil && status != existing.Status && !model.CanTransitionJob(existing.Status, status):
+		fail(fmt.Errorf("cannot move job from %s to %s", existing.Status, status))
+	}
+
+	if len(errs) > 0 {
+		return plannedImport{}, errs
+	}
+
+	job.ExternalRef = row.ExternalRef
+	if existing != nil && row.ExternalRef == "" {
+		job.ExternalRef = existing.ExternalRef
+	}
+	job.Title = row.Title
+	job.Description = row.Description
+	job.Location = placement.Label
+	job.WorkMode = placement.WorkMode
+	job.RemoteCountries = placement.RemoteCountries
+	job.Salary = row.Salary
+	job.Compensation = compensation
+	job.CategoryID = categoryID
+	job.Seniority = row.Seniority
+	job.EmploymentType = row.EmploymentType
+	if row.ExpiresAt != nil {
+		job.ExpiresAt = row.ExpiresAt
+	}
+	if status == model.JobPublished && job.Status != model.JobPublished {
+		job.PublishedAt = &now
+	}
+	job.Status = status
+	job.Locations = nil
+	job.Skills = nil
+
+	return plannedImport{job: job, before: before, skills: row.Skills, locations: placement.Locations}, nil
+}
+
+// writeImportPlan creates or updates the planned job inside tx
+func writeImportPlan(tx *gorm.DB, plan *plannedImport) error {
+	skills, err := helpers.ResolveSkills(tx, plan.skills)
+	if err != nil {
+		return err
+	}
+	if plan.job.ID == 0 {
+		plan.job.Skills = skills
+		plan.job.Locations = plan.locations
+		if err := tx.Create(&plan.job).Error; err != nil {
+			return err
+		}
+		plan.result.JobID = plan.job.ID
+		return nil
+	}
+
+	if err := tx.Save(&plan.job).Error; err != nil {
+		return err
+	}
+	if err := tx.Model(&plan.job).Association("Skills").Replace(skills); err != nil {
+		return err
+	}
+	plan.job.Skills = skills
+	return replaceJobLocations(tx, &plan.job, plan.locations)
+}
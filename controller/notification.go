@@ -0,0 +1,90 @@
+package controller
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sahilq312/workly/initializer"
+	"github.com/sahilq312/workly/model"
+)
+
+// GetNotifications lists the current user's notifications, newest first.
+// Pass unread=true to only get unread ones.
+func GetNotifications(c *gin.Context) {
+	user, ok := c.Get("user")
+	if !ok || user == nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not found"})
+		return
+	}
+	userModel := user.(model.User)
+
+	page := 1
+	perPage := 20
+	if pageStr := c.Query("page"); pageStr != "" {
+		if p, err := strconv.Atoi(pageStr); err == nil && p > 0 {
+			page = p
+		}
+	}
+
+	query := initializer.DB.Model(&model.Notification{}).Where("user_id = ?", userModel.ID)
+	if c.Query("unread") == "true" {
+		query = query.Where("read_at IS NULL")
+	}
+
+	var unread int64
+	if err := initializer.DB.Model(&model.Notification{}).Where("user_id = ? AND read_at IS NULL", userModel.ID).Count(&unread).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch notifications"})
+		return
+	}
+
+	var notifications []model.Notification
+	if err := query.Order("created_at DESC").Offset((page - 1) * perPage).Limit(perPage).Find(&notifications).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch notifications"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"notifications": notifications, "unread": unread, "page": page})
+}
+
+// MarkNotificationRead marks one of the current user's notifications as read
+func MarkNotificationRead(c *gin.Context) {
+	user, ok := c.Get("user")
+	if !ok || user == nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not found"})
+		return
+	}
+	userModel := user.(model.User)
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid notification ID"})
+		return
+	}
+	result := initializer.DB.Model(&model.Notification{}).
+		Where("id = ? AND user_id = ? AND read_at IS NULL", uint(id), userModel.ID).
+		Update("read_at", time.Now())
+	if result.Error != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update notification"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Notification marked as read"})
+}
+
+// MarkAllNotificationsRead marks every unread notification of the current user as read
+func MarkAllNotificationsRead(c *gin.Context) {
+	user, ok := c.Get("user")
+	if !ok || user == nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not found"})
+		return
+	}
+	userModel := user.(model.User)
+
+	if err := initializer.DB.Model(&model.Notification{}).
+		Where("user_id = ? AND read_at IS NULL", userModel.ID).
+		Update("read_at", time.Now()).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update notifications"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "All notifications marked as read"})
+}
@@ -0,0 +1,192 @@
+package controller
+
+import (
+	"fmt"
+	"html/template"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sahilq312/workly/helpers"
+	"github.com/sahilq312/workly/initializer"
+	"github.com/sahilq312/workly/model"
+	"github.com/sahilq312/workly/seo"
+	"gorm.io/gorm"
+)
+
+// How long clients and proxies may reuse the sitemap
+const sitemapMaxAge = time.Hour
+
+// GetJobPage renders the public page of a job with its JobPosting structured
+// data. Requests for an outdated slug are redirected to the current one.
+func GetJobPage(c *gin.Context) {
+	id, ok := seo.SlugID(c.Param("slug"))
+	if !ok {
+		c.String(http.StatusNotFound, "Job not found")
+		return
+	}
+	var job model.Job
+	err := initializer.Reader(c.Request.Context()).
+		Preload("Company").Preload("Skills").Preload("Locations").Preload("Category").
+		Where("status NOT IN ?", []string{model.JobDraft, model.JobPaused}).
+		First(&job, id).Error
+	if err != nil || job.Company.ID == 0 {
+		c.String(http.StatusNotFound, "Job not found")
+		return
+	}
+	if redirectToCanonical(c, seo.JobPath(job)) {
+		return
+	}
+	job.Redact()
+
+	page := seo.JobPage{
+		Page: seo.Page{
+			Title:       job.Title + " at " + job.Company.Name,
+			Description: jobPageDescription(job),
+			Canonical:   helpers.JobURL(job),
+		},
+		Job:        job,
+		CompanyURL: helpers.CompanyURL(job.Company),
+		Open:       job.IsOpen(time.Now()),
+	}
+	// Search engines should only see postings that can still be applied to
+	if page.Open {
+		posting, err := seo.JobPosting(job, page.Canonical, page.CompanyURL)
+		if err != nil {
+			c.String(http.StatusInternalServerError, "Failed to render job")
+			return
+		}
+		page.StructuredData = template.JS(posting)
+	} else {
+		page.NoIndex = true
+	}
+	c.HTML(http.StatusOK, "job.html", page)
+}
+
+// GetCompanyPage renders the public page of a company with its open jobs
+func GetCompanyPage(c *gin.Context) {
+	id, ok := seo.SlugID(c.Param("slug"))
+	if !ok {
+		c.String(http.StatusNotFound, "Company not found")
+		return
+	}
+	db := initializer.Reader(c.Request.Context())
+	var company model.Company
+	if err := db.First(&company, id).Error; err != nil {
+		c.String(http.StatusNotFound, "Company not found")
+		return
+	}
+	if redirectToCanonical(c, seo.CompanyPath(company)) {
+		return
+	}
+
+	var jobs []model.Job
+	err := helpers.JobFilter{}.Apply(db.Model(&model.Job{})).
+		Preload("Locations").
+		Where("jobs.company_id = ?", company.ID).
+		Order("jobs.published_at DESC, jobs.id DESC").
+		Find(&jobs).Error
+	if err != nil {
+		c.String(http.StatusInternalServerError, "Failed to render company")
+		return
+	}
+	links := make([]seo.JobLink, len(jobs))
+	for i, job := range jobs {
+		job.Company = company
+		job.Redact()
+		links[i] = seo.JobLink{Title: job.Title, URL: helpers.JobURL(job), Location: job.LocationLabel(), Salary: job.SalaryLabel()}
+	}
+
+	canonical := helpers.CompanyURL(company)
+	organization, err := seo.Organization(company, canonical)
+	if err != nil {
+		c.String(http.StatusInternalServerError, "Failed to render company")
+		return
+	}
+	c.HTML(http.StatusOK, "company.html", seo.CompanyPage{
+		Page: seo.Page{
+			Title:          "Jobs at " + company.Name,
+			Description:    fmt.Sprintf("%d open jobs at %s", len(jobs), company.Name),
+			Canonical:      canonical,
+			StructuredData: template.JS(organization),
+		},
+		Company: company,
+		Jobs:    links,
+	})
+}
+
+// GetSitemap lists the pages of open jobs and of companies hiring for them
+func GetSitemap(c *gin.Context) {
+	db := initializer.Reader(c.Request.Context())
+	var jobs []model.Job
+	err := helpers.JobFilter{}.Apply(db.Model(&model.Job{})).
+		Select("jobs.id, jobs.title, jobs.company_id, jobs.updated_at").
+		Preload("Company", func(db *gorm.DB) *gorm.DB { return db.Select("id", "name") }).
+		Order("jobs.published_at DESC, jobs.id DESC").
+		Limit(seo.MaxSitemapURLs).
+		Find(&jobs).Error
+	if err != nil {
+		c.String(http.StatusInternalServerError, "Failed to build sitemap")
+		return
+	}
+	var companies []model.Company
+	err = db.Select("id", "name", "updated_at").
+		Where("id IN (?)", helpers.JobFilter{}.Apply(db.Model(&model.Job{})).Select("jobs.company_id")).
+		Order("id").
+		Find(&companies).Error
+	if err != nil {
+		c.String(http.StatusInternalServerError, "Failed to build sitemap")
+		return
+	}
+
+	urls := make([]seo.SitemapURL, 0, len(jobs)+len(companies))
+	for _, company := range companies {
+		urls = append(urls, seo.NewSitemapURL(helpers.CompanyURL(company), company.UpdatedAt, "daily"))
+	}
+	for _, job := range jobs {
+		if job.Company.ID != 0 {
+			urls = append(urls, seo.NewSitemapURL(helpers.JobURL(job), job.UpdatedAt, "weekly"))
+		}
+	}
+	if len(urls) > seo.MaxSitemapURLs {
+		urls = urls[:seo.MaxSitemapURLs]
+	}
+	body, err := seo.Sitemap(urls)
+	if err != nil {
+		c.String(http.StatusInternalServerError, "Failed to build sitemap")
+		return
+	}
+	c.Header("Cache-Control", fmt.Sprintf("public, max-age=%d", int(sitemapMaxAge.Seconds())))
+	c.Data(http.StatusOK, "application/xml; charset=utf-8", body)
+}
+
+// GetRobots points crawlers at the sitemap
+func GetRobots(c *gin.Context) {
+	c.String(http.StatusOK, "User-agent: *\nAllow: /\nSitemap: %s\n", helpers.PublicURL("/sitemap.xml"))
+}
+
+// redirectToCanonical sends a permanent redirect when the request path is not
+// the canonical one, keeping the query string, and reports whether it did
+func redirectToCanonical(c *gin.Context, canonical string) bool {
+	if c.Request.URL.Path == canonical {
+		return false
+	}
+	location := canonical
+	if c.Request.URL.RawQuery != "" {
+		location += "?" + c.Request.URL.RawQuery
+	}
+	c.Redirect(http.StatusMovedPermanently, location)
+	return true
+}
+
+// jobPageDescription is the meta description of a job page
+func jobPageDescription(job model.Job) string {
+	description := job.Title + " at " + job.Company.Name
+	if location := job.LocationLabel(); location != "" {
+		description += " in " + location
+	}
+	if salary := job.SalaryLabel(); salary != "" {
+		description += ", " + salary
+	}
+	return description + "."
+}
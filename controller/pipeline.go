@@ -0,0 +1,479 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sahilq312/workly/helpers"
+	"github.com/sahilq312/workly/initializer"
+	"github.com/sahilq312/workly/model"
+	"gorm.io/gorm"
+)
+
+// PipelineColumn is one column of a job's board: a pipeline stage, or the
+// applications in a state the pipeline has no stage for
+type PipelineColumn struct {
+	Stage        *model.PipelineStage `json:"stage"`
+	Status       string               `json:"status"`
+	Count        int                  `json:"count"`
+	Applications []model.Application  `json:"applications"`
+}
+
+// CreatePipelineTemplate saves a reusable list of stages for the company
+func CreatePipelineTemplate(c *gin.Context) {
+	companyID, ok := pipelineCompanyID(c)
+	if !ok {
+		return
+	}
+	var body struct {
+		Name   string               `json:"name"`
+		Stages []helpers.StageInput `json:"stages"`
+	}
+	if err := c.ShouldBindJSON(&body); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+	template := model.PipelineTemplate{CompanyID: companyID, Name: strings.TrimSpace(body.Name)}
+	if template.Name == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Name is required"})
+		return
+	}
+	stages, err := helpers.BuildPipelineStages(body.Stages)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	for i := range stages {
+		stages[i].ID = 0
+	}
+	template.Stages = stages
+	if err := initializer.DB.Create(&template).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create pipeline template"})
+		return
+	}
+	c.JSON(http.StatusCreated, gin.H{"message": "Pipeline template created successfully", "data": template})
+}
+
+// GetPipelineTemplates lists the company's pipeline templates with their stages
+func GetPipelineTemplates(c *gin.Context) {
+	companyID, ok := pipelineCompanyID(c)
+	if !ok {
+		return
+	}
+	templates := []model.PipelineTemplate{}
+	if err := initializer.Reader(c.Request.Context()).
+		Preload("Stages", orderByPosition).
+		Where("company_id = ?", companyID).
+		Order("name").
+		Find(&templates).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch pipeline templates"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": templates})
+}
+
+// UpdatePipelineTemplate renames a template and replaces its stages. Jobs
+// that already copied the template keep their own stages.
+func UpdatePipelineTemplate(c *gin.Context) {
+	companyID, ok := pipelineCompanyID(c)
+	if !ok {
+		return
+	}
+	var body struct {
+		Name   *string               `json:"name"`
+		Stages *[]helpers.StageInput `json:"stages"`
+	}
+	if err := c.ShouldBindJSON(&body); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid template ID"})
+		return
+	}
+	var template model.PipelineTemplate
+	if err := initializer.DB.Where("company_id = ?", companyID).First(&template, id).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Pipeline template not found"})
+		return
+	}
+	if body.Name != nil {
+		template.Name = strings.TrimSpace(*body.Name)
+		if template.Name == "" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Name is required"})
+			return
+		}
+	}
+	var stages []model.PipelineStage
+	if body.Stages != nil {
+		if stages, err = helpers.BuildPipelineStages(*body.Stages); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+
+	err = initializer.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&template).Update("name", template.Name).Error; err != nil {
+			return err
+		}
+		if body.Stages == nil {
+			return nil
+		}
+		if err := tx.Where("template_id = ?", template.ID).Delete(&model.PipelineStage{}).Error; err != nil {
+			return err
+		}
+		for i := range stages {
+			stages[i].ID = 0
+			stages[i].TemplateID = &template.ID
+		}
+		return tx.Create(&stages).Error
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update pipeline template"})
+		return
+	}
+	initializer.DB.Preload("Stages", orderByPosition).First(&template, template.ID)
+	c.JSON(http.StatusOK, gin.H{"message": "Pipeline template updated successfully", "data": template})
+}
+
+// DeletePipelineTemplate deletes one of the company's templates
+func DeletePipelineTemplate(c *gin.Context) {
+	companyID, ok := pipelineCompanyID(c)
+	if !ok {
+		return
+	}
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid template ID"})
+		return
+	}
+	result := initializer.DB.Where("company_id = ?", companyID).Delete(&model.PipelineTemplate{}, id)
+	if result.Error != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete pipeline template"})
+		return
+	}
+	if result.RowsAffected == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Pipeline template not found"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Pipeline template deleted successfully"})
+}
+
+// GetJobPipeline returns the stages of one of the company's jobs
+func GetJobPipeline(c *gin.Context) {
+	companyID, ok := pipelineCompanyID(c)
+	if !ok {
+		return
+	}
+	job, ok := pipelineJob(c, companyID)
+	if !ok {
+		return
+	}
+	stages, err := helpers.JobStages(initializer.DB, job.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch pipeline"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"job_id": job.ID, "stages": stages})
+}
+
+// SetJobPipeline replaces the stages of a job, either with a list of stages
+// or with a copy of one of the company's templates. Existing stages are
+// kept when their id or name is given again, so the applications in them
+// stay put; stages that still hold applications cannot be removed.
+func SetJobPipeline(c *gin.Context) {
+	companyID, ok := pipelineCompanyID(c)
+	if !ok {
+		return
+	}
+	var body struct {
+		TemplateID uint                 `json:"template_id"`
+		Stages     []helpers.StageInput `json:"stages"`
+	}
+	if err := c.ShouldBindJSON(&body); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+	if (body.TemplateID == 0) == (body.Stages == nil) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Send either template_id or stages"})
+		return
+	}
+	job, ok := pipelineJob(c, companyID)
+	if !ok {
+		return
+	}
+
+	inputs := body.Stages
+	if body.TemplateID != 0 {
+		var template model.PipelineTemplate
+		if err := initializer.DB.Preload("Stages", orderByPosition).
+			Where("company_id = ?", companyID).
+			First(&template, body.TemplateID).Error; err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Pipeline template not found"})
+			return
+		}
+		inputs = make([]helpers.StageInput, len(template.Stages))
+		for i, stage := range template.Stages {
+			inputs[i] = helpers.StageInput{Name: stage.Name, Category: stage.Category}
+		}
+	}
+	stages, err := helpers.BuildPipelineStages(inputs)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	before, err := helpers.JobStages(initializer.DB, job.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update pipeline"})
+		return
+	}
+	err = initializer.DB.Transaction(func(tx *gorm.DB) error {
+		return replaceJobStages(tx, job.ID, before, stages)
+	})
+	var badRequest errBadRequest
+	if errors.As(err, &badRequest) {
+		c.JSON(http.StatusConflict, gin.H{"error": string(badRequest)})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update pipeline"})
+		return
+	}
+
+	helpers.RecordAudit(c, helpers.AuditEntry{
+		Action:     model.AuditJobPipeline,
+		EntityType: "job",
+		EntityID:   job.ID,
+		Before:     gin.H{"stages": before},
+		After:      gin.H{"stages": stages},
+	})
+	c.JSON(http.StatusOK, gin.H{"message": "Pipeline updated successfully", "job_id": job.ID, "stages": stages})
+}
+
+// replaceJobStages swaps the stages of a job for a new ordered set. Stages
+// matched to an existing one by id, or else by name, are updated in place.
+// Applications without a stage are then placed in the first stage of their
+// status' category.
+func replaceJobStages(tx *gorm.DB, jobID uint, existing, stages []model.PipelineStage) error {
+	byID := map[uint]model.PipelineStage{}
+	byName := map[string]model.PipelineStage{}
+	for _, stage := range existing {
+		byID[stage.ID] = stage
+		byName[strings.ToLower(stage.Name)] = stage
+	}
+	kept := map[uint]bool{}
+	for i := range stages {
+		stages[i].JobID = &jobID
+		if stages[i].ID != 0 {
+			if _, ok := byID[stages[i].ID]; !ok {
+				return errBadRequest("Stage " + strconv.FormatUint(uint64(stages[i].ID), 10) + " does not belong to this job")
+			}
+		} else if previous, ok := byName[strings.ToLower(stages[i].Name)]; ok && !kept[previous.ID] {
+			stages[i].ID = previous.ID
+		}
+		if stages[i].ID == 0 {
+			continue
+		}
+		if kept[stages[i].ID] {
+			return errBadRequest("Stage " + strconv.FormatUint(uint64(stages[i].ID), 10) + " is listed twice")
+		}
+		kept[stages[i].ID] = true
+		// Applications take their status from the category of their stage
+		if previous := byID[stages[i].ID]; previous.Category != stages[i].Category {
+			var count int64
+			if err := tx.Model(&model.Application{}).Where("stage_id = ?", previous.ID).Count(&count).Error; err != nil {
+				return err
+			}
+			if count > 0 {
+				return errBadRequest("Cannot change the category of " + previous.Name + " while it holds applications")
+			}
+		}
+	}
+
+	var removed []uint
+	for _, stage := range existing {
+		if !kept[stage.ID] {
+			removed = append(removed, stage.ID)
+		}
+	}
+	if len(removed) > 0 {
+		var count int64
+		if err := tx.Model(&model.Application{}).Where("stage_id IN ?", removed).Count(&count).Error; err != nil {
+			return err
+		}
+		if count > 0 {
+			return errBadRequest("Move the applications out of the stages you are removing first")
+		}
+		if err := tx.Where("id IN ?", removed).Delete(&model.PipelineStage{}).Error; err != nil {
+			return err
+		}
+	}
+	for i := range stages {
+		if stages[i].ID == 0 {
+			if err := tx.Create(&stages[i]).Error; err != nil {
+				return err
+			}
+			continue
+		}
+		if err := tx.Model(&stages[i]).Select("Position", "Name", "Category").Updates(&stages[i]).Error; err != nil {
+			return err
+		}
+	}
+
+	for _, status := range model.ApplicationStatuses {
+		stage := helpers.FirstStage(stages, status)
+		if stage == nil {
+			continue
+		}
+		if err := tx.Model(&model.Application{}).
+			Where("job_id = ? AND stage_id IS NULL AND status = ?", jobID, status).
+			Update("stage_id", stage.ID).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// GetPipelineBoard groups the applications of one of the company's jobs by
+// pipeline stage, kanban style. Applications in a state the pipeline has no
+// stage for get a column of their own after the stages. The optional status
+// query parameter limits the board to some states, e.g.
+// status=applied,screening,interview,offer.
+func GetPipelineBoard(c *gin.Context) {
+	companyID, ok := pipelineCompanyID(c)
+	if !ok {
+		return
+	}
+	job, ok := pipelineJob(c, companyID)
+	if !ok {
+		return
+	}
+	db := initializer.Reader(c.Request.Context())
+	query := db.Preload("User").Where("job_id = ?", job.ID)
+	if statuses := c.Query("status"); statuses != "" {
+		filter := strings.Split(statuses, ",")
+		for _, status := range filter {
+			if !helpers.ContainsString(model.ApplicationStatuses, status) {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "status must be a list of " + strings.Join(model.ApplicationStatuses, ", ")})
+				return
+			}
+		}
+		query = query.Where("status IN ?", filter)
+	}
+	stages, err := helpers.JobStages(db, job.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch pipeline"})
+		return
+	}
+	var applications []model.Application
+	if err := query.Order("applied_at, id").Find(&applications).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch applications"})
+		return
+	}
+
+	columns := make([]PipelineColumn, len(stages))
+	column := map[uint]int{}
+	for i := range stages {
+		columns[i] = PipelineColumn{Stage: &stages[i], Status: stages[i].Category, Applications: []model.Application{}}
+		column[stages[i].ID] = i
+	}
+	unstaged := map[string]int{}
+	for _, application := range applications {
+		i, ok := -1, false
+		if application.StageID != nil {
+			i, ok = column[*application.StageID]
+		}
+		if !ok {
+			if stage := helpers.FirstStage(stages, application.Status); stage != nil {
+				i, ok = column[stage.ID], true
+			}
+		}
+		if !ok {
+			if i, ok = unstaged[application.Status]; !ok {
+				i = len(columns)
+				unstaged[application.Status] = i
+				columns = append(columns, PipelineColumn{Status: application.Status, Applications: []model.Application{}})
+			}
+		}
+		columns[i].Applications = append(columns[i].Applications, application)
+		columns[i].Count++
+	}
+
+	c.JSON(http.StatusOK, gin.H{"job_id": job.ID, "total": len(applications), "columns": columns})
+}
+
+// MoveApplicationStage moves an application to another stage of its job's
+// pipeline. Moving to a stage of another category changes the application's
+// status, so only the transitions of the status machine are allowed.
+func MoveApplicationStage(c *gin.Context) {
+	companyID, ok := pipelineCompanyID(c)
+	if !ok {
+		return
+	}
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid application ID"})
+		return
+	}
+	var body struct {
+		StageID uint   `json:"stage_id"`
+		Note    string `json:"note"`
+	}
+	if err := c.ShouldBindJSON(&body); err != nil || body.StageID == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "stage_id is required"})
+		return
+	}
+	application, ok := companyApplication(c, companyID, id)
+	if !ok {
+		return
+	}
+	var stage model.PipelineStage
+	if err := initializer.DB.Where("job_id = ?", application.JobID).First(&stage, body.StageID).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Stage not found in this job's pipeline"})
+		return
+	}
+	if application.StageID != nil && *application.StageID == stage.ID {
+		c.JSON(http.StatusConflict, gin.H{"error": "Application is already in " + stage.Name})
+		return
+	}
+	if stage.Category == model.ApplicationWithdrawn {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Only the candidate can withdraw an application"})
+		return
+	}
+	moveApplication(c, companyID, application, stage.Category, &stage, body.Note)
+}
+
+// pipelineCompanyID returns the ID of the authenticated company. It writes
+// the error response itself and reports false when there is none.
+func pipelineCompanyID(c *gin.Context) (uint, bool) {
+	company, ok := c.Get("company")
+	if !ok || company == nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Company not found"})
+		return 0, false
+	}
+	companyModel, ok := company.(model.Company)
+	if !ok || companyModel.ID == 0 {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized access"})
+		return 0, false
+	}
+	return companyModel.ID, true
+}
+
+// pipelineJob loads the job named by the id parameter if the company owns it
+func pipelineJob(c *gin.Context, companyID uint) (model.Job, bool) {
+	var job model.Job
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid job ID"})
+		return job, false
+	}
+	if err := initializer.DB.Where("company_id = ?", companyID).First(&job, id).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Job not found"})
+		return job, false
+	}
+	return job, true
+}
@@ -54,7 +54,7 @@ func GetPost(c *gin.Context) {
 	}
 
 	var post model.Post
-	if result := initializer.DB.First(&post, uint(id)); result.Error != nil {
+	if result := initializer.Reader(c.Request.Context()).First(&post, uint(id)); result.Error != nil {
 		c.JSON(http.StatusNotFound, gin.H{"error": "Post not found"})
 		return
 	}
@@ -65,7 +65,7 @@ func GetPost(c *gin.Context) {
 // GetPosts retrieves all posts
 func GetPosts(c *gin.Context) {
 	var posts []model.Post
-	if result := initializer.DB.Find(&posts); result.Error != nil {
+	if result := initializer.Reader(c.Request.Context()).Find(&posts); result.Error != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve posts"})
 		return
 	}
@@ -0,0 +1,121 @@
+package controller
+
+import (
+	"net/http"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sahilq312/workly/helpers"
+	"github.com/sahilq312/workly/initializer"
+	"github.com/sahilq312/workly/matching"
+	"github.com/sahilq312/workly/model"
+)
+
+// Most recent open jobs considered when ranking recommendations
+const recommendationPool = 500
+
+// Recommendation is a job suggested to a candidate with the reasons for it
+type Recommendation struct {
+	Job model.Job `json:"job"`
+	matching.Match
+}
+
+// GetJobRecommendations ranks open jobs for the current user by how well they
+// match their skills, experience, location and past applications. Optional
+// query parameters: location (overrides the inferred preference), min_score
+// and limit.
+func GetJobRecommendations(c *gin.Context) {
+	user, ok := c.Get("user")
+	if !ok || user == nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not found"})
+		return
+	}
+	userModel, ok := user.(model.User)
+	if !ok || userModel.ID == 0 {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid user ID"})
+		return
+	}
+
+	limit := 20
+	if limitStr := c.Query("limit"); limitStr != "" {
+		l, err := strconv.Atoi(limitStr)
+		if err != nil || l < 1 || l > 50 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "limit must be between 1 and 50"})
+			return
+		}
+		limit = l
+	}
+	var minScore float64
+	if minStr := c.Query("min_score"); minStr != "" {
+		m, err := strconv.ParseFloat(minStr, 64)
+		if err != nil || m < 0 || m > 100 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "min_score must be between 0 and 100"})
+			return
+		}
+		minScore = m
+	}
+
+	db := initializer.Reader(c.Request.Context())
+	var candidate model.User
+	if err := db.Preload("Skills").Preload("Experience.Skills").First(&candidate, userModel.ID).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+		return
+	}
+	var applications []model.Application
+	if err := db.Preload("Job.Skills").Preload("Job.Locations").Where("user_id = ?", userModel.ID).Find(&applications).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch applications"})
+		return
+	}
+
+	now := time.Now()
+	profile := matching.NewProfile(candidate, applications, now)
+	if location := c.Query("location"); location != "" {
+		profile.Places = nil
+		if !profile.AddPlace(location) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Unknown location: " + location})
+			return
+		}
+	}
+
+	query := helpers.JobFilter{}.Apply(db.Model(&model.Job{}))
+	if len(profile.Applied) > 0 {
+		applied := make([]uint, 0, len(profile.Applied))
+		for id := range profile.Applied {
+			applied = append(applied, id)
+		}
+		query = query.Where("jobs.id NOT IN ?", applied)
+	}
+	if len(profile.Skills) > 0 {
+		skills := make([]string, 0, len(profile.Skills))
+		for key := range profile.Skills {
+			skills = append(skills, key)
+		}
+		query = query.Where("jobs.id IN (SELECT job_skills.job_id FROM job_skills JOIN skills ON skills.id = job_skills.skill_id WHERE skills.key IN ?)", skills)
+	}
+
+	var jobs []model.Job
+	if err := query.Preload("Skills").Preload("Locations").Order("jobs.published_at DESC").Limit(recommendationPool).Find(&jobs).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch jobs"})
+		return
+	}
+
+	recommendations := []Recommendation{}
+	for _, job := range jobs {
+		match := matching.Score(profile, job, now)
+		if match.Score < minScore {
+			continue
+		}
+		job.Redact()
+		recommendations = append(recommendations, Recommendation{Job: job, Match: match})
+	}
+	sort.SliceStable(recommendations, func(i, j int) bool {
+		return recommendations[i].Score > recommendations[j].Score
+	})
+	if len(recommendations) > limit {
+		recommendations = recommendations[:limit]
+	}
+
+	c.JSON(http.StatusOK, gin.H{"recommendations": recommendations})
+}
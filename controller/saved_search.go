@@ -0,0 +1,266 @@
+package controller
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sahilq312/workly/helpers"
+	"github.com/sahilq312/workly/initializer"
+	"github.com/sahilq312/workly/model"
+	"github.com/sahilq312/workly/seo"
+	"github.com/sahilq312/workly/utils"
+)
+
+// CreateSavedSearch stores a job search for alerts. query is the query string
+// of a GET /job/ request, e.g. "search=golang&near=Berlin&radius_km=30".
+func CreateSavedSearch(c *gin.Context) {
+	user, ok := c.Get("user")
+	if !ok || user == nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not found"})
+		return
+	}
+	userModel, ok := user.(model.User)
+	if !ok || userModel.ID == 0 {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid user ID"})
+		return
+	}
+
+	var body struct {
+		Name         string `json:"name"`
+		Query        string `json:"query"`
+		Frequency    string `json:"frequency"`
+		EmailEnabled *bool  `json:"email_enabled"`
+	}
+	if err := c.ShouldBindJSON(&body); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+	if body.Name == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Name is required"})
+		return
+	}
+	if body.Frequency == "" {
+		body.Frequency = model.AlertDaily
+	}
+	if !validAlertFrequency(body.Frequency) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "frequency must be instant, daily or weekly"})
+		return
+	}
+
+	query, filters, err := parseSavedSearchQuery(body.Query)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	token, err := utils.RandomToken(24)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create saved search"})
+		return
+	}
+
+	search := model.SavedSearch{
+		UserID:           userModel.ID,
+		Name:             body.Name,
+		Query:            query,
+		Filters:          filters,
+		Frequency:        body.Frequency,
+		EmailEnabled:     body.EmailEnabled == nil || *body.EmailEnabled,
+		Active:           true,
+		UnsubscribeToken: token,
+		LastCheckedAt:    time.Now(),
+	}
+	if err := initializer.DB.Create(&search).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create saved search"})
+		return
+	}
+	c.JSON(http.StatusCreated, gin.H{"data": search})
+}
+
+// GetSavedSearches lists the current user's saved searches
+func GetSavedSearches(c *gin.Context) {
+	user, ok := c.Get("user")
+	if !ok || user == nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not found"})
+		return
+	}
+	userModel, ok := user.(model.User)
+	if !ok || userModel.ID == 0 {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid user ID"})
+		return
+	}
+
+	var searches []model.SavedSearch
+	if err := initializer.DB.Where("user_id = ?", userModel.ID).Order("created_at DESC").Find(&searches).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch saved searches"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": searches})
+}
+
+// UpdateSavedSearch changes the name, query, frequency or delivery of a saved search
+func UpdateSavedSearch(c *gin.Context) {
+	user, ok := c.Get("user")
+	if !ok || user == nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not found"})
+		return
+	}
+	userModel, ok := user.(model.User)
+	if !ok || userModel.ID == 0 {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid user ID"})
+		return
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid saved search ID"})
+		return
+	}
+	var search model.SavedSearch
+	if err := initializer.DB.Where("user_id = ?", userModel.ID).First(&search, id).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Saved search not found"})
+		return
+	}
+
+	var body struct {
+		Name         *string `json:"name"`
+		Query        *string `json:"query"`
+		Frequency    *string `json:"frequency"`
+		EmailEnabled *bool   `json:"email_enabled"`
+		Active       *bool   `json:"active"`
+	}
+	if err := c.ShouldBindJSON(&body); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+
+	if body.Name != nil && *body.Name != "" {
+		search.Name = *body.Name
+	}
+	if body.Query != nil {
+		query, filters, err := parseSavedSearchQuery(*body.Query)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		search.Query, search.Filters = query, filters
+	}
+	if body.Frequency != nil {
+		if !validAlertFrequency(*body.Frequency) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "frequency must be instant, daily or weekly"})
+			return
+		}
+		search.Frequency = *body.Frequency
+	}
+	if body.EmailEnabled != nil {
+		search.EmailEnabled = *body.EmailEnabled
+	}
+	if body.Active != nil {
+		// Resuming alerts should not replay everything missed while paused
+		if *body.Active && !search.Active {
+			search.LastCheckedAt = time.Now()
+		}
+		search.Active = *body.Active
+	}
+
+	if err := initializer.DB.Save(&search).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update saved search"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": search})
+}
+
+// DeleteSavedSearch removes one of the current user's saved searches
+func DeleteSavedSearch(c *gin.Context) {
+	user, ok := c.Get("user")
+	if !ok || user == nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not found"})
+		return
+	}
+	userModel, ok := user.(model.User)
+	if !ok || userModel.ID == 0 {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid user ID"})
+		return
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid saved search ID"})
+		return
+	}
+	result := initializer.DB.Where("user_id = ?", userModel.ID).Delete(&model.SavedSearch{}, uint(id))
+	if result.Error != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete saved search"})
+		return
+	}
+	if result.RowsAffected == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Saved search not found"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Saved search deleted successfully"})
+}
+
+// GetUnsubscribeSavedSearch shows the confirmation page for the unsubscribe
+// link in an alert email. It changes nothing, so mail scanners and link
+// prefetchers that follow the link cannot unsubscribe anyone.
+func GetUnsubscribeSavedSearch(c *gin.Context) {
+	var search model.SavedSearch
+	if err := initializer.DB.Where("unsubscribe_token = ?", c.Param("token")).First(&search).Error; err != nil {
+		c.String(http.StatusNotFound, "Unknown unsubscribe link")
+		return
+	}
+	c.Header("Cache-Control", "no-store")
+	c.HTML(http.StatusOK, "unsubscribe.html", seo.UnsubscribePage{
+		SearchName:   search.Name,
+		Action:       c.Request.URL.Path,
+		Unsubscribed: !search.Active,
+	})
+}
+
+// UnsubscribeSavedSearch turns off alerts for a saved search. It needs no
+// login and answers both the confirmation form and RFC 8058 one-click POSTs.
+func UnsubscribeSavedSearch(c *gin.Context) {
+	var search model.SavedSearch
+	if err := initializer.DB.Where("unsubscribe_token = ?", c.Param("token")).First(&search).Error; err != nil {
+		c.String(http.StatusNotFound, "Unknown unsubscribe link")
+		return
+	}
+	if err := initializer.DB.Model(&search).Update("active", false).Error; err != nil {
+		c.String(http.StatusInternalServerError, "Failed to unsubscribe")
+		return
+	}
+	c.Header("Cache-Control", "no-store")
+	c.HTML(http.StatusOK, "unsubscribe.html", seo.UnsubscribePage{SearchName: search.Name, Unsubscribed: true})
+}
+
+// parseSavedSearchQuery validates a GET /job/ query string and returns it
+// without pagination together with its parsed filters
+func parseSavedSearchQuery(raw string) (string, model.JSON, error) {
+	values, err := url.ParseQuery(strings.TrimPrefix(raw, "?"))
+	if err != nil {
+		return "", nil, err
+	}
+	values.Del("page")
+
+	filter, err := helpers.ParseJobFilterQuery(values)
+	if err != nil {
+		return "", nil, err
+	}
+	filters, err := json.Marshal(filter)
+	if err != nil {
+		return "", nil, err
+	}
+	return values.Encode(), model.JSON(filters), nil
+}
+
+func validAlertFrequency(frequency string) bool {
+	switch frequency {
+	case model.AlertInstant, model.AlertDaily, model.AlertWeekly:
+		return true
+	}
+	return false
+}
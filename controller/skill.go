@@ -0,0 +1,325 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sahilq312/workly/helpers"
+	"github.com/sahilq312/workly/initializer"
+	"github.com/sahilq312/workly/model"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// SkillSuggestion is an autocomplete result. Alias is set when the query
+// matched another name of the skill rather than its own.
+type SkillSuggestion struct {
+	ID       uint   `json:"id"`
+	Name     string `json:"name"`
+	Alias    string `json:"alias,omitempty"`
+	Category string `json:"category,omitempty"`
+	Jobs     int64  `json:"jobs"`
+}
+
+// AutocompleteSkills suggests canonical skills whose name or alias starts
+// with q, most used first
+func AutocompleteSkills(c *gin.Context) {
+	prefix := model.SkillKey(c.Query("q"))
+	if prefix == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "q is required"})
+		return
+	}
+	limit := 10
+	if limitStr := c.Query("limit"); limitStr != "" {
+		if l, err := strconv.Atoi(limitStr); err == nil && l > 0 && l <= 50 {
+			limit = l
+		}
+	}
+	pattern := escapeLike(prefix) + "%"
+
+	var suggestions []SkillSuggestion
+	err := initializer.Reader(c.Request.Context()).Table("skills").
+		Select(`skills.id, skills.name, skill_categories.name AS category,
+			(SELECT a.name FROM skill_aliases a WHERE a.skill_id = skills.id AND a.deleted_at IS NULL AND a.key LIKE ? ORDER BY a.key LIMIT 1) AS alias,
+			(SELECT COUNT(*) FROM job_skills WHERE job_skills.skill_id = skills.id) AS jobs`, pattern).
+		Joins("LEFT JOIN skill_categories ON skill_categories.id = skills.category_id").
+		Where("skills.deleted_at IS NULL").
+		Where("skills.key LIKE ? OR skills.id IN (SELECT skill_id FROM skill_aliases WHERE key LIKE ? AND deleted_at IS NULL)", pattern, pattern).
+		Order(clause.OrderBy{Expression: clause.Expr{SQL: "skills.key = ? DESC, jobs DESC, skills.name", Vars: []interface{}{prefix}}}).
+		Limit(limit).
+		Scan(&suggestions).Error
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch skills"})
+		return
+	}
+	for i := range suggestions {
+		if strings.HasPrefix(model.SkillKey(suggestions[i].Name), prefix) {
+			suggestions[i].Alias = ""
+		}
+	}
+	if suggestions == nil {
+		suggestions = []SkillSuggestion{}
+	}
+	c.JSON(http.StatusOK, gin.H{"skills": suggestions})
+}
+
+// GetSkills lists canonical skills with their aliases, optionally within a category
+func GetSkills(c *gin.Context) {
+	query := initializer.Reader(c.Request.Context()).Preload("Aliases").Preload("Category").Order("name")
+	if raw := c.Query("category_id"); raw != "" {
+		categoryID, err := strconv.ParseUint(raw, 10, 32)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid category ID"})
+			return
+		}
+		query = query.Where("category_id = ?", categoryID)
+	}
+	var skills []model.Skill
+	if err := query.Find(&skills).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch skills"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"skills": skills})
+}
+
+// GetSkillCategories returns the category tree
+func GetSkillCategories(c *gin.Context) {
+	var categories []model.SkillCategory
+	if err := initializer.Reader(c.Request.Context()).Order("name").Find(&categories).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch categories"})
+		return
+	}
+
+	children := map[uint][]model.SkillCategory{}
+	var roots []model.SkillCategory
+	for _, category := range categories {
+		if category.ParentID == nil {
+			roots = append(roots, category)
+		} else {
+			children[*category.ParentID] = append(children[*category.ParentID], category)
+		}
+	}
+	var attach func(list []model.SkillCategory) []model.SkillCategory
+	attach = func(list []model.SkillCategory) []model.SkillCategory {
+		for i := range list {
+			list[i].Children = attach(children[list[i].ID])
+		}
+		return list
+	}
+	c.JSON(http.StatusOK, gin.H{"categories": attach(roots)})
+}
+
+// CreateSkillCategory adds a category, optionally under a parent
+func CreateSkillCategory(c *gin.Context) {
+	var body struct {
+		Name     string `json:"name"`
+		ParentID *uint  `json:"parent_id"`
+	}
+	if err := c.ShouldBindJSON(&body); err != nil || strings.TrimSpace(body.Name) == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Name is required"})
+		return
+	}
+	if body.ParentID != nil {
+		if err := initializer.DB.First(&model.SkillCategory{}, *body.ParentID).Error; err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Parent category not found"})
+			return
+		}
+	}
+	category := model.SkillCategory{Name: strings.TrimSpace(body.Name), ParentID: body.ParentID}
+	if err := initializer.DB.Create(&category).Error; err != nil {
+		c.JSON(http.StatusConflict, gin.H{"error": "A category with this name already exists"})
+		return
+	}
+	c.JSON(http.StatusCreated, gin.H{"data": category})
+}
+
+// UpdateSkill renames a skill or moves it to another category. The previous
+// name keeps working as an alias.
+func UpdateSkill(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid skill ID"})
+		return
+	}
+	var skill model.Skill
+	if err := initializer.DB.First(&skill, id).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Skill not found"})
+		return
+	}
+	var body struct {
+		Name       *string `json:"name"`
+		CategoryID *uint   `json:"category_id"`
+	}
+	if err := c.ShouldBindJSON(&body); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+	before := skill
+
+	err = initializer.DB.Transaction(func(tx *gorm.DB) error {
+		if body.Name != nil && model.CleanSkillName(*body.Name) != skill.Name {
+			key := model.SkillKey(*body.Name)
+			if key == "" {
+				return errBadRequest("Name cannot be empty")
+			}
+			if other, found, err := helpers.FindSkill(tx, key); err != nil {
+				return err
+			} else if found && other.ID != skill.ID {
+				return errBadRequest("Another skill is already known by this name; merge them instead")
+			}
+			if err := helpers.AddSkillAlias(tx, model.Skill{Model: skill.Model, Key: key}, skill.Name); err != nil {
+				return err
+			}
+			// The new name must not linger as an alias of itself
+			if err := tx.Unscoped().Where("skill_id = ? AND key = ?", skill.ID, key).Delete(&model.SkillAlias{}).Error; err != nil {
+				return err
+			}
+			skill.Name, skill.Key = model.CleanSkillName(*body.Name), key
+		}
+		if body.CategoryID != nil {
+			if *body.CategoryID == 0 {
+				skill.CategoryID = nil
+			} else if err := tx.First(&model.SkillCategory{}, *body.CategoryID).Error; err != nil {
+				return errBadRequest("Category not found")
+			} else {
+				skill.CategoryID = body.CategoryID
+			}
+		}
+		return tx.Select("Name", "Key", "CategoryID").Save(&skill).Error
+	})
+	var badRequest errBadRequest
+	if errors.As(err, &badRequest) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": string(badRequest)})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update skill"})
+		return
+	}
+
+	helpers.RecordAudit(c, helpers.AuditEntry{
+		Action:     model.AuditSkillUpdate,
+		EntityType: "skill",
+		EntityID:   skill.ID,
+		Before:     gin.H{"name": before.Name, "category_id": before.CategoryID},
+		After:      gin.H{"name": skill.Name, "category_id": skill.CategoryID},
+	})
+	c.JSON(http.StatusOK, gin.H{"data": skill})
+}
+
+// MergeSkills folds duplicate skills into a target skill. Jobs, users and
+// experiences are moved over and the duplicates' names become aliases.
+func MergeSkills(c *gin.Context) {
+	var body struct {
+		TargetID  uint   `json:"target_id"`
+		SourceIDs []uint `json:"source_ids"`
+	}
+	if err := c.ShouldBindJSON(&body); err != nil || body.TargetID == 0 || len(body.SourceIDs) == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "target_id and source_ids are required"})
+		return
+	}
+
+	var target model.Skill
+	var sources []model.Skill
+	err := initializer.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.First(&target, body.TargetID).Error; err != nil {
+			return errBadRequest("Target skill not found")
+		}
+		if err := tx.Where("id IN ? AND id <> ?", body.SourceIDs, target.ID).Find(&sources).Error; err != nil {
+			return err
+		}
+		if len(sources) == 0 {
+			return errBadRequest("No source skills found")
+		}
+		return helpers.MergeSkills(tx, target, sources)
+	})
+	var badRequest errBadRequest
+	if errors.As(err, &badRequest) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": string(badRequest)})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to merge skills"})
+		return
+	}
+
+	merged := make([]gin.H, len(sources))
+	for i, source := range sources {
+		merged[i] = gin.H{"id": source.ID, "name": source.Name}
+	}
+	helpers.RecordAudit(c, helpers.AuditEntry{
+		Action:     model.AuditSkillMerge,
+		EntityType: "skill",
+		EntityID:   target.ID,
+		Before:     gin.H{"merged": merged},
+		After:      gin.H{"name": target.Name},
+	})
+	c.JSON(http.StatusOK, gin.H{"message": "Skills merged successfully", "merged": len(sources)})
+}
+
+// AddSkillAlias records another name for a skill
+func AddSkillAlias(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid skill ID"})
+		return
+	}
+	var skill model.Skill
+	if err := initializer.DB.First(&skill, id).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Skill not found"})
+		return
+	}
+	var body struct {
+		Name string `json:"name"`
+	}
+	if err := c.ShouldBindJSON(&body); err != nil || model.SkillKey(body.Name) == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Name is required"})
+		return
+	}
+	other, found, err := helpers.FindSkill(initializer.DB, model.SkillKey(body.Name))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to add alias"})
+		return
+	}
+	if found && other.ID != skill.ID && other.Key == model.SkillKey(body.Name) {
+		c.JSON(http.StatusConflict, gin.H{"error": "This name belongs to another skill; merge them instead"})
+		return
+	}
+	if err := helpers.AddSkillAlias(initializer.DB, skill, body.Name); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to add alias"})
+		return
+	}
+	c.JSON(http.StatusCreated, gin.H{"message": "Alias added successfully"})
+}
+
+// DeleteSkillAlias removes an alias
+func DeleteSkillAlias(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid alias ID"})
+		return
+	}
+	result := initializer.DB.Unscoped().Delete(&model.SkillAlias{}, id)
+	if result.Error != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete alias"})
+		return
+	}
+	if result.RowsAffected == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Alias not found"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Alias deleted successfully"})
+}
+
+// errBadRequest carries a client error out of a transaction
+type errBadRequest string
+
+func (e errBadRequest) Error() string { return string(e) }
+
+// escapeLike escapes LIKE wildcards in user input
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(s)
+}
@@ -0,0 +1,98 @@
+package feed
+
+import (
+	"encoding/xml"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/sahilq312/workly/model"
+)
+
+// The aggregator format follows the <source><job> layout job boards commonly
+// ingest. Free text is wrapped in CDATA as those parsers expect.
+type aggregatorSource struct {
+	XMLName       xml.Name        `xml:"source"`
+	Publisher     string          `xml:"publisher"`
+	PublisherURL  string          `xml:"publisherurl"`
+	LastBuildDate string          `xml:"lastBuildDate"`
+	Jobs          []aggregatorJob `xml:"job"`
+}
+
+type aggregatorJob struct {
+	Title           cdata  `xml:"title"`
+	Date            string `xml:"date"`
+	ReferenceNumber string `xml:"referencenumber"`
+	URL             string `xml:"url"`
+	Company         cdata  `xml:"company"`
+	City            cdata  `xml:"city"`
+	State           cdata  `xml:"state"`
+	Country         string `xml:"country"`
+	RemoteType      string `xml:"remotetype,omitempty"`
+	RemoteCountries string `xml:"remotecountries,omitempty"`
+	Description     cdata  `xml:"description"`
+	Salary          cdata  `xml:"salary"`
+	// Structured pay, only when the company shows it
+	SalaryMin      string `xml:"salarymin,omitempty"`
+	SalaryMax      string `xml:"salarymax,omitempty"`
+	SalaryCurrency string `xml:"salarycurrency,omitempty"`
+	SalaryPeriod   string `xml:"salaryperiod,omitempty"`
+	JobType        string `xml:"jobtype,omitempty"`
+	Experience     string `xml:"experience,omitempty"`
+	Category       cdata  `xml:"category"`
+	Skills         cdata  `xml:"skills"`
+	ExpirationDate string `xml:"expirationdate,omitempty"`
+}
+
+type cdata struct {
+	Value string `xml:",cdata"`
+}
+
+func aggregator(meta Meta, jobs []model.Job) aggregatorSource {
+	source := aggregatorSource{
+		Publisher:     meta.Publisher,
+		PublisherURL:  meta.Link,
+		LastBuildDate: meta.Updated.UTC().Format(time.RFC1123Z),
+	}
+	for _, job := range jobs {
+		entry := aggregatorJob{
+			Title:           cdata{job.Title},
+			Date:            published(job).UTC().Format(time.RFC1123Z),
+			ReferenceNumber: strconv.FormatUint(uint64(job.ID), 10),
+			URL:             meta.JobURL(job),
+			Company:         cdata{job.Company.Name},
+			City:            cdata{job.Location},
+			Description:     cdata{job.Description},
+			Salary:          cdata{job.SalaryLabel()},
+			JobType:         job.EmploymentType,
+			Experience:      job.Seniority,
+			Skills:          cdata{strings.Join(skillNames(job), ", ")},
+		}
+		// The first location is the primary one; the free-form location is kept otherwise
+		if len(job.Locations) > 0 {
+			primary := job.Locations[0]
+			entry.City, entry.State, entry.Country = cdata{primary.City}, cdata{primary.Region}, primary.Country
+		}
+		if job.WorkMode == model.WorkRemote || job.WorkMode == model.WorkHybrid {
+			entry.RemoteType = job.WorkMode
+			entry.RemoteCountries = strings.Join(job.RemoteCountries, ",")
+		}
+		if comp := job.Compensation; !comp.Hidden && !comp.IsZero() {
+			if comp.Min != nil {
+				entry.SalaryMin = strconv.FormatInt(*comp.Min, 10)
+			}
+			if comp.Max != nil {
+				entry.SalaryMax = strconv.FormatInt(*comp.Max, 10)
+			}
+			entry.SalaryCurrency, entry.SalaryPeriod = comp.Currency, comp.Period
+		}
+		if job.Category != nil {
+			entry.Category = cdata{job.Category.Name}
+		}
+		if job.ExpiresAt != nil {
+			entry.ExpirationDate = job.ExpiresAt.UTC().Format(time.RFC3339)
+		}
+		source.Jobs = append(source.Jobs, entry)
+	}
+	return source
+}
@@ -0,0 +1,95 @@
+package feed
+
+import (
+	"encoding/xml"
+	"time"
+
+	"github.com/sahilq312/workly/model"
+)
+
+type atomFeed struct {
+	XMLName  xml.Name    `xml:"http://www.w3.org/2005/Atom feed"`
+	ID       string      `xml:"id"`
+	Title    string      `xml:"title"`
+	Subtitle string      `xml:"subtitle,omitempty"`
+	Updated  string      `xml:"updated"`
+	Links    []atomLink  `xml:"link"`
+	Author   atomPerson  `xml:"author"`
+	Entries  []atomEntry `xml:"entry"`
+}
+
+type atomLink struct {
+	Href string `xml:"href,attr"`
+	Rel  string `xml:"rel,attr,omitempty"`
+	Type string `xml:"type,attr,omitempty"`
+}
+
+type atomPerson struct {
+	Name string `xml:"name"`
+}
+
+type atomEntry struct {
+	ID         string         `xml:"id"`
+	Title      string         `xml:"title"`
+	Link       atomLink       `xml:"link"`
+	Published  string         `xml:"published"`
+	Updated    string         `xml:"updated"`
+	Author     *atomPerson    `xml:"author,omitempty"`
+	Summary    string         `xml:"summary,omitempty"`
+	Content    *atomText      `xml:"content,omitempty"`
+	Categories []atomCategory `xml:"category"`
+}
+
+type atomText struct {
+	Type  string `xml:"type,attr"`
+	Value string `xml:",chardata"`
+}
+
+type atomCategory struct {
+	Term   string `xml:"term,attr"`
+	Scheme string `xml:"scheme,attr,omitempty"`
+	Label  string `xml:"label,attr,omitempty"`
+}
+
+func atom(meta Meta, jobs []model.Job) atomFeed {
+	feed := atomFeed{
+		ID:       meta.Self,
+		Title:    meta.Title,
+		Subtitle: meta.Description,
+		Updated:  meta.Updated.UTC().Format(time.RFC3339),
+		Links: []atomLink{
+			{Href: meta.Self, Rel: "self", Type: "application/atom+xml"},
+			{Href: meta.Link, Rel: "alternate"},
+		},
+		Author: atomPerson{Name: meta.Publisher},
+	}
+	for _, job := range jobs {
+		link := meta.JobURL(job)
+		updated := job.UpdatedAt
+		if pub := published(job); pub.After(updated) {
+			updated = pub
+		}
+		entry := atomEntry{
+			ID:        link,
+			Title:     job.Title,
+			Link:      atomLink{Href: link, Rel: "alternate"},
+			Published: published(job).UTC().Format(time.RFC3339),
+			Updated:   updated.UTC().Format(time.RFC3339),
+			Summary:   summary(job),
+		}
+		if job.Company.Name != "" {
+			entry.Author = &atomPerson{Name: job.Company.Name}
+		}
+		if job.Description != "" {
+			entry.Content = &atomText{Type: "text", Value: job.Description}
+		}
+		if job.Category != nil {
+			entry.Categories = append(entry.Categories, atomCategory{Term: job.Category.Slug, Scheme: "category", Label: job.Category.Name})
+		}
+		for _, skill := range skillNames(job) {
+			entry.Categories = append(entry.Categories, atomCategory{Term: skill, Scheme: "skill"})
+		}
+		feed.Entries = append(feed.Entries, entry)
+	}
+	return feed
+}
@@ -0,0 +1,94 @@
+// Package feed renders job listings as RSS 2.0, Atom 1.0 and the XML format
+// read by job aggregators.
+package feed
+
+import (
+	"encoding/xml"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/sahilq312/workly/model"
+)
+
+// Feed formats
+const (
+	FormatRSS  = "rss"
+	FormatAtom = "atom"
+	FormatJobs = "jobs"
+)
+
+// ContentTypes maps each format to the media type it is served with
+var ContentTypes = map[string]string{
+	FormatRSS:  "application/rss+xml; charset=utf-8",
+	FormatAtom: "application/atom+xml; charset=utf-8",
+	FormatJobs: "application/xml; charset=utf-8",
+}
+
+// Meta describes a feed as a whole. Link is the human-facing listing, Self the
+// feed's own URL and JobURL builds the link to a single job.
+type Meta struct {
+	Title       string
+	Description string
+	Link        string
+	Self        string
+	Publisher   string
+	Updated     time.Time
+	JobURL      func(model.Job) string
+}
+
+// Render writes jobs in the given format. Jobs need Company, Skills, Locations
+// and Category loaded, with hidden pay already redacted.
+func Render(format string, meta Meta, jobs []model.Job) ([]byte, error) {
+	var doc interface{}
+	switch format {
+	case FormatRSS:
+		doc = rss(meta, jobs)
+	case FormatAtom:
+		doc = atom(meta, jobs)
+	case FormatJobs:
+		doc = aggregator(meta, jobs)
+	default:
+		return nil, fmt.Errorf("unsupported feed format %q", format)
+	}
+	out, err := xml.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte(xml.Header), out...), nil
+}
+
+// published is when a job went live, falling back to its creation for jobs
+// published before the date was recorded
+func published(job model.Job) time.Time {
+	if job.PublishedAt != nil {
+		return *job.PublishedAt
+	}
+	return job.CreatedAt
+}
+
+// summary is a one-line description of where and how a job is done
+func summary(job model.Job) string {
+	var parts []string
+	if job.Company.Name != "" {
+		parts = append(parts, job.Company.Name)
+	}
+	if where := job.LocationLabel(); where != "" {
+		parts = append(parts, where)
+	}
+	if job.WorkMode != "" && job.WorkMode != model.WorkOnsite {
+		parts = append(parts, job.WorkMode)
+	}
+	if pay := job.SalaryLabel(); pay != "" {
+		parts = append(parts, pay)
+	}
+	return strings.Join(parts, " · ")
+}
+
+func skillNames(job model.Job) []string {
+	names := make([]string, len(job.Skills))
+	for i, skill := range job.Skills {
+		names[i] = skill.Name
+	}
+	return names
+}
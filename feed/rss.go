@@ -0,0 +1,90 @@
+package feed
+
+import (
+	"encoding/xml"
+	"strconv"
+	"time"
+
+	"github.com/sahilq312/workly/model"
+)
+
+type rssDocument struct {
+	XMLName xml.Name   `xml:"rss"`
+	Version string     `xml:"version,attr"`
+	AtomNS  string     `xml:"xmlns:atom,attr"`
+	Channel rssChannel `xml:"channel"`
+}
+
+type rssChannel struct {
+	Title         string    `xml:"title"`
+	Link          string    `xml:"link"`
+	Description   string    `xml:"description"`
+	AtomLink      rssSelf   `xml:"atom:link"`
+	LastBuildDate string    `xml:"lastBuildDate"`
+	TTL           int       `xml:"ttl,omitempty"`
+	Items         []rssItem `xml:"item"`
+}
+
+type rssSelf struct {
+	Href string `xml:"href,attr"`
+	Rel  string `xml:"rel,attr"`
+	Type string `xml:"type,attr"`
+}
+
+type rssItem struct {
+	Title       string        `xml:"title"`
+	Link        string        `xml:"link"`
+	GUID        rssGUID       `xml:"guid"`
+	PubDate     string        `xml:"pubDate"`
+	Description string        `xml:"description"`
+	Categories  []rssCategory `xml:"category"`
+}
+
+type rssGUID struct {
+	Value       string `xml:",chardata"`
+	IsPermaLink bool   `xml:"isPermaLink,attr"`
+}
+
+type rssCategory struct {
+	Domain string `xml:"domain,attr,omitempty"`
+	Value  string `xml:",chardata"`
+}
+
+// Minutes feed readers are asked to wait between polls
+const rssTTL = 15
+
+func rss(meta Meta, jobs []model.Job) rssDocument {
+	channel := rssChannel{
+		Title:         meta.Title,
+		Link:          meta.Link,
+		Description:   meta.Description,
+		AtomLink:      rssSelf{Href: meta.Self, Rel: "self", Type: "application/rss+xml"},
+		LastBuildDate: meta.Updated.UTC().Format(time.RFC1123Z),
+		TTL:           rssTTL,
+	}
+	for _, job := range jobs {
+		link := meta.JobURL(job)
+		description := summary(job)
+		if job.Description != "" {
+			if description != "" {
+				description += "\n\n"
+			}
+			description += job.Description
+		}
+		item := rssItem{
+			Title:       job.Title,
+			Link:        link,
+			GUID:        rssGUID{Value: "job-" + strconv.FormatUint(uint64(job.ID), 10), IsPermaLink: false},
+			PubDate:     published(job).UTC().Format(time.RFC1123Z),
+			Description: description,
+		}
+		if job.Category != nil {
+			item.Categories = append(item.Categories, rssCategory{Domain: "category", Value: job.Category.Name})
+		}
+		for _, skill := range skillNames(job) {
+			item.Categories = append(item.Categories, rssCategory{Domain: "skill", Value: skill})
+		}
+		channel.Items = append(channel.Items, item)
+	}
+	return rssDocument{Version: "2.0", AtomNS: "http://www.w3.org/2005/Atom", Channel: channel}
+}
@@ -0,0 +1,163 @@
+package geo
+
+import (
+	"bufio"
+	"bytes"
+	_ "embed"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+
+	"golang.org/x/text/cases"
+	"golang.org/x/text/unicode/norm"
+)
+
+// Mean Earth radius used for great-circle distances
+const EarthRadiusKm = 6371.0
+
+//go:embed gazetteer.csv
+var defaultGazetteer []byte
+
+// Place is a gazetteer entry
+type Place struct {
+	City      string  `json:"city"`
+	Region    string  `json:"region"`
+	Country   string  `json:"country"`
+	Latitude  float64 `json:"latitude"`
+	Longitude float64 `json:"longitude"`
+}
+
+var (
+	mu     sync.RWMutex
+	places map[string][]Place
+)
+
+// Load replaces the gazetteer with the CSV file at path. An empty path loads
+// the gazetteer bundled with the binary. No network access is ever needed.
+func Load(path string) error {
+	data := defaultGazetteer
+	if path != "" {
+		var err error
+		if data, err = os.ReadFile(path); err != nil {
+			return fmt.Errorf("geo: reading gazetteer: %w", err)
+		}
+	}
+	index, err := parse(bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	mu.Lock()
+	places = index
+	mu.Unlock()
+	return nil
+}
+
+// parse reads lines of name,region,country,latitude,longitude,aliases
+func parse(r io.Reader) (map[string][]Place, error) {
+	index := make(map[string][]Place)
+	scanner := bufio.NewScanner(r)
+	line := 0
+	for scanner.Scan() {
+		line++
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+		fields := strings.Split(text, ",")
+		if len(fields) < 5 {
+			return nil, fmt.Errorf("geo: gazetteer line %d: expected at least 5 fields", line)
+		}
+		lat, err := strconv.ParseFloat(fields[3], 64)
+		if err != nil {
+			return nil, fmt.Errorf("geo: gazetteer line %d: bad latitude", line)
+		}
+		lon, err := strconv.ParseFloat(fields[4], 64)
+		if err != nil {
+			return nil, fmt.Errorf("geo: gazetteer line %d: bad longitude", line)
+		}
+		place := Place{City: fields[0], Region: fields[1], Country: strings.ToUpper(fields[2]), Latitude: lat, Longitude: lon}
+
+		names := []string{place.City}
+		if len(fields) > 5 && fields[5] != "" {
+			names = append(names, strings.Split(fields[5], "|")...)
+		}
+		for _, name := range names {
+			key := normalize(name)
+			index[key] = append(index[key], place)
+		}
+	}
+	return index, scanner.Err()
+}
+
+// Lookup finds a city by name. The name may be qualified with a region or
+// ISO country code ("Cambridge, GB") to pick between places sharing a name;
+// otherwise the first listed place wins.
+func Lookup(name string) (Place, bool) {
+	mu.RLock()
+	defer mu.RUnlock()
+	if places == nil {
+		return Place{}, false
+	}
+
+	parts := strings.Split(name, ",")
+	candidates := places[normalize(parts[0])]
+	if len(candidates) == 0 {
+		return Place{}, false
+	}
+	if len(parts) > 1 {
+		qualifier := normalize(parts[len(parts)-1])
+		for _, place := range candidates {
+			if normalize(place.Country) == qualifier || normalize(place.Region) == qualifier {
+				return place, true
+			}
+		}
+		return Place{}, false
+	}
+	return candidates[0], true
+}
+
+// LookupIn finds a city by name within a country, or anywhere when country is empty
+func LookupIn(city, country string) (Place, bool) {
+	if country == "" {
+		return Lookup(city)
+	}
+	return Lookup(city + "," + country)
+}
+
+// DistanceKm returns the great-circle distance between two points
+func DistanceKm(lat1, lon1, lat2, lon2 float64) float64 {
+	dLat := radians(lat2 - lat1)
+	dLon := radians(lon2 - lon1)
+	a := math.Sin(dLat/2)*math.Sin(dLat/2) +
+		math.Cos(radians(lat1))*math.Cos(radians(lat2))*math.Sin(dLon/2)*math.Sin(dLon/2)
+	return 2 * EarthRadiusKm * math.Asin(math.Sqrt(a))
+}
+
+func radians(deg float64) float64 {
+	return deg * math.Pi / 180
+}
+
+var folder = cases.Fold()
+
+// normalize folds case and strips accents so "Zürich" matches "zurich"
+func normalize(s string) string {
+	decomposed := norm.NFD.String(strings.TrimSpace(s))
+	var b strings.Builder
+	for _, r := range decomposed {
+		if r >= 0x300 && r <= 0x36f { // combining diacritical marks
+			continue
+		}
+		b.WriteRune(r)
+	}
+	return folder.String(b.String())
+}
+
+func init() {
+	if err := Load(""); err != nil {
+		panic(err)
+	}
+}
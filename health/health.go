@@ -0,0 +1,87 @@
+package health
+
+import (
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+var shuttingDown atomic.Bool
+
+// SetShuttingDown marks the service as draining so readiness probes fail
+func SetShuttingDown() {
+	shuttingDown.Store(true)
+}
+
+// ShuttingDown reports whether graceful shutdown has started
+func ShuttingDown() bool {
+	return shuttingDown.Load()
+}
+
+type worker struct {
+	interval time.Duration
+	started  time.Time
+	lastBeat time.Time
+	lastErr  string
+}
+
+var (
+	workersMu sync.Mutex
+	workers   = map[string]*worker{}
+)
+
+// RegisterWorker announces a background worker that is expected to call Beat
+// at least once per interval
+func RegisterWorker(name string, interval time.Duration) {
+	workersMu.Lock()
+	defer workersMu.Unlock()
+	workers[name] = &worker{interval: interval, started: time.Now()}
+}
+
+// Beat records that a worker completed a run. A non-nil err is reported in
+// probe output but does not make the worker unhealthy on its own.
+func Beat(name string, err error) {
+	workersMu.Lock()
+	defer workersMu.Unlock()
+	w, ok := workers[name]
+	if !ok {
+		return
+	}
+	w.lastBeat = time.Now()
+	w.lastErr = ""
+	if err != nil {
+		w.lastErr = err.Error()
+	}
+}
+
+// WorkerStatus is the probe view of a background worker
+type WorkerStatus struct {
+	Name      string     `json:"name"`
+	Healthy   bool       `json:"healthy"`
+	LastBeat  *time.Time `json:"last_beat,omitempty"`
+	LastError string     `json:"last_error,omitempty"`
+}
+
+// Workers reports every registered worker. A worker is unhealthy once it has
+// gone more than two intervals without a beat.
+func Workers() []WorkerStatus {
+	workersMu.Lock()
+	defer workersMu.Unlock()
+
+	now := time.Now()
+	statuses := make([]WorkerStatus, 0, len(workers))
+	for name, w := range workers {
+		last := w.started
+		status := WorkerStatus{Name: name, LastError: w.lastErr}
+		if !w.lastBeat.IsZero() {
+			beat := w.lastBeat
+			status.LastBeat = &beat
+			last = beat
+		}
+		status.Healthy = now.Sub(last) <= 2*w.interval
+		statuses = append(statuses, status)
+	}
+	sort.Slice(statuses, func(i, j int) bool { return statuses[i].Name < statuses[j].Name })
+	return statuses
+}
@@ -0,0 +1,88 @@
+package helpers
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/sahilq312/workly/model"
+	"gorm.io/gorm"
+)
+
+// Longest note that may accompany a status change
+const maxStatusNoteLength = 1000
+
+// ErrStatusConflict is returned when an application changed state while a
+// transition was being applied
+var ErrStatusConflict = errors.New("application status was changed by someone else")
+
+// StatusMove describes a change to an application's state. Stage is the
+// pipeline stage it lands in, or nil when the job has no matching stage.
+type StatusMove struct {
+	Status    string
+	Stage     *model.PipelineStage
+	ActorType string
+	ActorID   uint
+	Note      string
+}
+
+// ValidateStatusChange checks that an application may move to the given state
+// and normalises the note that goes with it. Staying in the same state is
+// allowed so applications can move between stages of one category.
+func ValidateStatusChange(application model.Application, to, note string) (string, error) {
+	if !ContainsString(model.ApplicationStatuses, to) {
+		return "", fmt.Errorf("status must be one of %s", strings.Join(model.ApplicationStatuses, ", "))
+	}
+	if to == application.Status && model.IsTerminalApplicationStatus(to) {
+		return "", fmt.Errorf("application is already %s", to)
+	}
+	if to != application.Status && !model.CanTransitionApplication(application.Status, to) {
+		return "", fmt.Errorf("cannot move an application from %s to %s", application.Status, to)
+	}
+	note = strings.TrimSpace(note)
+	if utf8.RuneCountInString(note) > maxStatusNoteLength {
+		return "", fmt.Errorf("note must be at most %d characters", maxStatusNoteLength)
+	}
+	return note, nil
+}
+
+// ChangeApplicationStatus moves an application to a new state and stage and
+// appends the change to its history. The application's Stage must be loaded.
+// The update only applies while the application is still in the state and
+// stage it was loaded in, so two concurrent changes cannot both succeed.
+func ChangeApplicationStatus(db *gorm.DB, application *model.Application, move StatusMove) (model.ApplicationStatusChange, error) {
+	change := model.ApplicationStatusChange{
+		ApplicationID: application.ID,
+		Event:         model.HistoryStatusChange,
+		FromStatus:    application.Status,
+		ToStatus:      move.Status,
+		FromStage:     StageName(application.Stage),
+		ToStage:       StageName(move.Stage),
+		ActorType:     move.ActorType,
+		ActorID:       move.ActorID,
+		Note:          move.Note,
+	}
+	stageID := StageID(move.Stage)
+	err := db.Transaction(func(tx *gorm.DB) error {
+		current := tx.Model(&model.Application{}).Where("id = ? AND status = ?", application.ID, application.Status)
+		if application.StageID == nil {
+			current = current.Where("stage_id IS NULL")
+		} else {
+			current = current.Where("stage_id = ?", *application.StageID)
+		}
+		result := current.Updates(map[string]interface{}{"status": move.Status, "stage_id": stageID})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrStatusConflict
+		}
+		return tx.Create(&change).Error
+	})
+	if err != nil {
+		return model.ApplicationStatusChange{}, err
+	}
+	application.Status, application.StageID, application.Stage = move.Status, stageID, move.Stage
+	return change, nil
+}
@@ -0,0 +1,128 @@
+package helpers
+
+import (
+	"encoding/json"
+	"log"
+	"reflect"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sahilq312/workly/initializer"
+	"github.com/sahilq312/workly/model"
+)
+
+// AuditEntry describes an action to be written to the audit log. ActorType and
+// ActorID are taken from the authenticated principal when left empty.
+type AuditEntry struct {
+	ActorType  string
+	ActorID    uint
+	Action     string
+	EntityType string
+	EntityID   uint
+	Before     interface{}
+	After      interface{}
+}
+
+// Fields that change on every write and only add noise to a diff
+var auditIgnoredFields = map[string]bool{
+	"created_at": true,
+	"updated_at": true,
+	"CreatedAt":  true,
+	"UpdatedAt":  true,
+}
+
+// RecordAudit appends an entry to the audit log. Failures are logged but never
+// fail the request that triggered them.
+func RecordAudit(c *gin.Context, entry AuditEntry) {
+	if entry.ActorType == "" {
+		entry.ActorType, entry.ActorID = auditActor(c)
+	}
+
+	before, after := auditDiff(entry.Before, entry.After)
+	record := model.AuditLog{
+		ActorType:  entry.ActorType,
+		ActorID:    entry.ActorID,
+		Action:     entry.Action,
+		EntityType: entry.EntityType,
+		EntityID:   entry.EntityID,
+		Before:     before,
+		After:      after,
+	}
+	if c != nil && c.Request != nil {
+		record.IP = c.ClientIP()
+		record.UserAgent = c.Request.UserAgent()
+		record.Method = c.Request.Method
+		record.Path = c.Request.URL.Path
+	}
+
+	if err := initializer.DB.Create(&record).Error; err != nil {
+		log.Printf("audit: failed to record %s on %s %d: %v", entry.Action, entry.EntityType, entry.EntityID, err)
+	}
+}
+
+// auditActor resolves the principal that is making the request
+func auditActor(c *gin.Context) (string, uint) {
+	if c == nil {
+		return model.ActorSystem, 0
+	}
+	if _, ok := c.Get("admin"); ok {
+		return model.ActorAdmin, 0
+	}
+	if company, ok := c.Get("company"); ok {
+		if companyModel, ok := company.(model.Company); ok {
+			return model.ActorCompany, companyModel.ID
+		}
+	}
+	if user, ok := c.Get("user"); ok {
+		if userModel, ok := user.(model.User); ok {
+			return model.ActorUser, userModel.ID
+		}
+	}
+	return model.ActorSystem, 0
+}
+
+// auditDiff reduces before and after to the top-level fields that differ.
+// When one side is nil (create or delete) the other side is kept whole.
+func auditDiff(before, after interface{}) (model.JSON, model.JSON) {
+	beforeMap := toFieldMap(before)
+	afterMap := toFieldMap(after)
+
+	if beforeMap != nil && afterMap != nil {
+		for key, value := range beforeMap {
+			if other, ok := afterMap[key]; ok && reflect.DeepEqual(value, other) {
+				delete(beforeMap, key)
+				delete(afterMap, key)
+			}
+		}
+	}
+	return marshalFieldMap(beforeMap), marshalFieldMap(afterMap)
+}
+
+func toFieldMap(value interface{}) map[string]interface{} {
+	if value == nil {
+		return nil
+	}
+	raw, err := json.Marshal(value)
+	if err != nil {
+		return nil
+	}
+	var fields map[string]interface{}
+	if err := json.Unmarshal(raw, &fields); err != nil {
+		// Not an object, keep it under a single key
+		return map[string]interface{}{"value": value}
+	}
+	for key := range auditIgnoredFields {
+		delete(fields, key)
+	}
+	return fields
+}
+
+func marshalFieldMap(fields map[string]interface{}) model.JSON {
+	if fields == nil {
+		return nil
+	}
+	raw, err := json.Marshal(fields)
+	if err != nil {
+		return nil
+	}
+	return model.JSON(raw)
+}
@@ -0,0 +1,227 @@
+package helpers
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/sahilq312/workly/initializer"
+	"github.com/sahilq312/workly/model"
+	"github.com/sahilq312/workly/storage"
+	"github.com/sahilq312/workly/utils"
+)
+
+// Document formats accepted for uploads, keyed by file extension
+const (
+	mimePDF  = "application/pdf"
+	mimeDOCX = "application/vnd.openxmlformats-officedocument.wordprocessingml.document"
+	mimeText = "text/plain; charset=utf-8"
+)
+
+var documentFormats = map[string]string{
+	".pdf":  mimePDF,
+	".docx": mimeDOCX,
+	".txt":  mimeText,
+}
+
+// Extensions accepted for each document kind
+var documentKinds = map[string][]string{
+	model.DocumentResume:      {".pdf", ".docx"},
+	model.DocumentCoverLetter: {".pdf", ".docx", ".txt"},
+}
+
+// Upload is a validated file waiting to be stored
+type Upload struct {
+	Kind        string
+	FileName    string
+	ContentType string
+	Data        []byte
+}
+
+// ReadUpload reads a multipart file of the given kind, checking its size and
+// that its content really is one of the formats accepted for that kind.
+// The declared Content-Type is ignored.
+func ReadUpload(header *multipart.FileHeader, kind string, maxSize int) (Upload, error) {
+	name := cleanFileName(header.Filename)
+	if header.Size > int64(maxSize) {
+		return Upload{}, fmt.Errorf("%s must be at most %d MB", name, maxSize>>20)
+	}
+	file, err := header.Open()
+	if err != nil {
+		return Upload{}, err
+	}
+	defer file.Close()
+	data, err := io.ReadAll(io.LimitReader(file, int64(maxSize)+1))
+	if err != nil {
+		return Upload{}, err
+	}
+	if len(data) > maxSize {
+		return Upload{}, fmt.Errorf("%s must be at most %d MB", name, maxSize>>20)
+	}
+	if len(data) == 0 {
+		return Upload{}, fmt.Errorf("%s is empty", name)
+	}
+
+	ext := strings.ToLower(filepath.Ext(name))
+	if !ContainsString(documentKinds[kind], ext) {
+		return Upload{}, fmt.Errorf("%s must be a %s file", strings.ReplaceAll(kind, "_", " "), strings.Join(documentKinds[kind], ", "))
+	}
+	contentType := sniffDocument(data)
+	if contentType != documentFormats[ext] {
+		return Upload{}, fmt.Errorf("%s is not a valid %s file", name, ext)
+	}
+	return Upload{Kind: kind, FileName: name, ContentType: contentType, Data: data}, nil
+}
+
+// sniffDocument identifies PDF, DOCX and plain text files from their content
+func sniffDocument(data []byte) string {
+	detected := http.DetectContentType(data)
+	switch {
+	case detected == mimePDF:
+		return mimePDF
+	case detected == "application/zip":
+		// DOCX files are zip archives holding a Word document part
+		archive, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+		if err != nil {
+			return detected
+		}
+		for _, file := range archive.File {
+			if file.Name == "word/document.xml" {
+				return mimeDOCX
+			}
+		}
+	case strings.HasPrefix(detected, "text/plain") && utf8.Valid(data):
+		return mimeText
+	}
+	return detected
+}
+
+// cleanFileName keeps the base name of an uploaded file without control characters
+func cleanFileName(name string) string {
+	name = filepath.Base(strings.ReplaceAll(name, "\\", "/"))
+	name = strings.Map(func(r rune) rune {
+		if unicode.IsControl(r) || r == '"' {
+			return -1
+		}
+		return r
+	}, name)
+	if len(name) > 200 {
+		ext := filepath.Ext(name)
+		name = strings.ToValidUTF8(name[:200-len(ext)], "") + ext
+	}
+	if name == "" || name == "." || name == "/" {
+		return "document"
+	}
+	return name
+}
+
+// StoreUploads saves uploads under prefix and returns their documents. When
+// any upload fails, the ones already stored are removed again.
+func StoreUploads(ctx context.Context, store storage.Storage, prefix string, uploads []Upload) ([]model.Document, error) {
+	var documents []model.Document
+	for _, upload := range uploads {
+		token, err := utils.RandomToken(16)
+		if err != nil {
+			DeleteDocuments(ctx, store, documents)
+			return nil, err
+		}
+		key := prefix + "/" + token + strings.ToLower(filepath.Ext(upload.FileName))
+		if err := store.Put(ctx, key, bytes.NewReader(upload.Data), int64(len(upload.Data)), upload.ContentType); err != nil {
+			DeleteDocuments(ctx, store, documents)
+			return nil, err
+		}
+		documents = append(documents, model.Document{
+			Kind:        upload.Kind,
+			FileName:    upload.FileName,
+			ContentType: upload.ContentType,
+			Size:        int64(len(upload.Data)),
+			StorageKey:  key,
+		})
+	}
+	return documents, nil
+}
+
+// DeleteDocuments removes the stored files of documents, logging failures
+func DeleteDocuments(ctx context.Context, store storage.Storage, documents []model.Document) {
+	for _, document := range documents {
+		if err := store.Delete(ctx, document.StorageKey); err != nil {
+			log.Printf("storage: failed to delete %s: %v", document.StorageKey, err)
+		}
+	}
+}
+
+// Download links are bound to the principal they were issued to and expire
+// after Config.Storage.DownloadURLTTL.
+
+// SignDocumentURLs fills in the download link of each document for a principal
+func SignDocumentURLs(documents []model.Document, actorType string, actorID uint) {
+	expires := time.Now().Add(initializer.Config.Storage.DownloadURLTTL).Unix()
+	for i := range documents {
+		principal := actorType + ":" + strconv.FormatUint(uint64(actorID), 10)
+		query := url.Values{
+			"principal": {principal},
+			"expires":   {strconv.FormatInt(expires, 10)},
+			"signature": {documentSignature(documents[i].ID, principal, expires)},
+		}
+		documents[i].URL = PublicURL("/document/" + strconv.FormatUint(uint64(documents[i].ID), 10) + "?" + query.Encode())
+	}
+}
+
+// VerifyDocumentURL checks a download link and returns the principal it was
+// issued to
+func VerifyDocumentURL(documentID uint, query url.Values) (actorType string, actorID uint, err error) {
+	principal := query.Get("principal")
+	expires, err := strconv.ParseInt(query.Get("expires"), 10, 64)
+	if err != nil {
+		return "", 0, errors.New("invalid download link")
+	}
+	signature, err := hex.DecodeString(query.Get("signature"))
+	if err != nil {
+		return "", 0, errors.New("invalid download link")
+	}
+	expected, _ := hex.DecodeString(documentSignature(documentID, principal, expires))
+	if !hmac.Equal(signature, expected) {
+		return "", 0, errors.New("invalid download link")
+	}
+	if time.Now().Unix() > expires {
+		return "", 0, errors.New("download link has expired")
+	}
+	actorType, id, ok := strings.Cut(principal, ":")
+	n, err := strconv.ParseUint(id, 10, 32)
+	if !ok || err != nil {
+		return "", 0, errors.New("invalid download link")
+	}
+	return actorType, uint(n), nil
+}
+
+func documentSignature(documentID uint, principal string, expires int64) string {
+	mac := hmac.New(sha256.New, documentSigningKey())
+	fmt.Fprintf(mac, "%d|%s|%d", documentID, principal, expires)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// documentSigningKey is the configured key, or one derived from the JWT secrets
+func documentSigningKey() []byte {
+	if key := initializer.Config.Storage.SigningKey; key != "" {
+		return []byte(key)
+	}
+	mac := hmac.New(sha256.New, []byte(initializer.Config.Auth.UserSecret+"\x00"+initializer.Config.Auth.CompanySecret))
+	mac.Write([]byte("document-download"))
+	return mac.Sum(nil)
+}
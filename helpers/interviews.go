@@ -0,0 +1,296 @@
+package helpers
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"net/mail"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/sahilq312/workly/ical"
+	"github.com/sahilq312/workly/initializer"
+	"github.com/sahilq312/workly/mailer"
+	"github.com/sahilq312/workly/model"
+)
+
+// Limits on interview details
+const (
+	minInterviewMinutes = 15
+	maxInterviewMinutes = 8 * 60
+	maxInterviewSlots   = 20
+	// Slots can be offered at most this far ahead
+	maxInterviewLead = 180 * 24 * time.Hour
+)
+
+// InterviewInput is an interview as sent by a company
+type InterviewInput struct {
+	Title           string      `json:"title"`
+	DurationMinutes int         `json:"duration_minutes"`
+	Timezone        string      `json:"timezone"`
+	Location        string      `json:"location"`
+	VideoURL        string      `json:"video_url"`
+	Instructions    string      `json:"instructions"`
+	InterviewerIDs  []uint      `json:"interviewer_ids"`
+	Slots           []time.Time `json:"slots"`
+}
+
+// BuildInterview validates an interview input and converts it to a model.
+// Interviewers are left for the caller to resolve.
+func BuildInterview(input InterviewInput, now time.Time) (model.Interview, error) {
+	interview := model.Interview{
+		Title:           strings.TrimSpace(input.Title),
+		DurationMinutes: input.DurationMinutes,
+		Timezone:        strings.TrimSpace(input.Timezone),
+		Location:        strings.TrimSpace(input.Location),
+		VideoURL:        strings.TrimSpace(input.VideoURL),
+		Instructions:    strings.TrimSpace(input.Instructions),
+		Status:          model.InterviewProposed,
+	}
+	if interview.Title == "" {
+		interview.Title = "Interview"
+	}
+	if interview.DurationMinutes < minInterviewMinutes || interview.DurationMinutes > maxInterviewMinutes {
+		return interview, fmt.Errorf("duration_minutes must be between %d and %d", minInterviewMinutes, maxInterviewMinutes)
+	}
+	if interview.Timezone == "" {
+		interview.Timezone = "UTC"
+	}
+	if _, err := time.LoadLocation(interview.Timezone); err != nil {
+		return interview, errors.New("timezone must be an IANA time zone such as Europe/Berlin")
+	}
+	if interview.VideoURL != "" {
+		u, err := url.Parse(interview.VideoURL)
+		if err != nil || (u.Scheme != "https" && u.Scheme != "http") || u.Host == "" {
+			return interview, errors.New("video_url must be an http or https link")
+		}
+	}
+	if interview.Location == "" && interview.VideoURL == "" {
+		return interview, errors.New("a location or video_url is required")
+	}
+	slots, err := BuildInterviewSlots(input.Slots, now)
+	if err != nil {
+		return interview, err
+	}
+	interview.Slots = slots
+	return interview, nil
+}
+
+// BuildInterviewSlots validates offered start times, dropping duplicates and
+// sorting them
+func BuildInterviewSlots(times []time.Time, now time.Time) ([]model.InterviewSlot, error) {
+	if len(times) == 0 {
+		return nil, errors.New("offer at least one slot")
+	}
+	if len(times) > maxInterviewSlots {
+		return nil, fmt.Errorf("offer at most %d slots", maxInterviewSlots)
+	}
+	sort.Slice(times, func(i, j int) bool { return times[i].Before(times[j]) })
+	var slots []model.InterviewSlot
+	for _, start := range times {
+		start = start.UTC().Truncate(time.Minute)
+		if !start.After(now) {
+			return nil, errors.New("slots must be in the future")
+		}
+		if start.Sub(now) > maxInterviewLead {
+			return nil, errors.New("slots can be at most 180 days ahead")
+		}
+		if len(slots) > 0 && start.Equal(slots[len(slots)-1].StartsAt) {
+			continue
+		}
+		slots = append(slots, model.InterviewSlot{StartsAt: start})
+	}
+	return slots, nil
+}
+
+// InterviewUID is the iCalendar UID shared by every invite for an interview
+func InterviewUID(interview model.Interview) string {
+	host := "workly"
+	if u, err := url.Parse(initializer.Config.Server.PublicURL); err == nil && u.Hostname() != "" {
+		host = u.Hostname()
+	}
+	return "interview-" + strconv.FormatUint(uint64(interview.ID), 10) + "@" + host
+}
+
+// InterviewEvent describes a booked interview as a calendar event. The
+// interview needs Interviewers and Application.User, Application.Job and
+// Application.Job.Company loaded.
+func InterviewEvent(interview model.Interview) ical.Event {
+	job := interview.Application.Job
+	candidate := interview.Application.User
+	event := ical.Event{
+		UID:         InterviewUID(interview),
+		Sequence:    interview.Sequence,
+		Stamp:       interview.UpdatedAt,
+		Start:       *interview.StartsAt,
+		End:         interview.EndsAt(),
+		Summary:     interview.Title + ": " + candidate.Name + " for " + job.Title + " at " + job.Company.Name,
+		Description: interviewDescription(interview),
+		Location:    interview.Location,
+		URL:         interview.VideoURL,
+		Status:      ical.StatusConfirmed,
+		Organizer:   &ical.Person{Name: job.Company.Name, Email: organizerEmail()},
+		Attendees:   []ical.Person{{Name: candidate.Name, Email: candidate.Email}},
+	}
+	if event.Location == "" {
+		event.Location = interview.VideoURL
+	}
+	if interview.Status == model.InterviewCancelled {
+		event.Status = ical.StatusCancelled
+	}
+	for _, interviewer := range interview.Interviewers {
+		event.Attendees = append(event.Attendees, ical.Person{Name: interviewer.Name, Email: interviewer.Email})
+	}
+	return event
+}
+
+// InterviewInvite renders a booked interview as a single-event calendar with
+// the given iTIP method
+func InterviewInvite(interview model.Interview, method string) []byte {
+	return ical.Calendar{
+		ProdID: calendarProdID,
+		Method: method,
+		Events: []ical.Event{InterviewEvent(interview)},
+	}.Marshal()
+}
+
+// The PRODID of every calendar we generate
+const calendarProdID = "-//Workly//Interviews//EN"
+
+// InterviewerFeed renders an interviewer's interviews as a subscribable calendar
+func InterviewerFeed(interviewer model.Interviewer, interviews []model.Interview) []byte {
+	calendar := ical.Calendar{
+		ProdID:          calendarProdID,
+		Name:            "Interviews: " + interviewer.Name,
+		RefreshInterval: time.Hour,
+	}
+	for _, interview := range interviews {
+		if interview.StartsAt != nil {
+			calendar.Events = append(calendar.Events, InterviewEvent(interview))
+		}
+	}
+	return calendar.Marshal()
+}
+
+// SetInterviewerFeedURLs fills in the calendar feed link of each interviewer
+func SetInterviewerFeedURLs(interviewers []model.Interviewer) {
+	for i := range interviewers {
+		interviewers[i].FeedURL = PublicURL("/interview/feed/" + interviewers[i].FeedToken + ".ics")
+	}
+}
+
+// SendInterviewInvites emails the invite, or its cancellation, to the
+// candidate and the interviewers. Failures are logged but never fail the
+// request that triggered them.
+func SendInterviewInvites(interview model.Interview, method string) {
+	invite := InterviewInvite(interview, method)
+	subject := "Invitation: " + interview.Title + " for " + interview.Application.Job.Title
+	if method == ical.MethodCancel {
+		subject = "Cancelled: " + interview.Title + " for " + interview.Application.Job.Title
+	}
+	recipients := []ical.Person{{Name: interview.Application.User.Name, Email: interview.Application.User.Email}}
+	for _, interviewer := range interview.Interviewers {
+		recipients = append(recipients, ical.Person{Name: interviewer.Name, Email: interviewer.Email})
+	}
+	for _, recipient := range recipients {
+		if recipient.Email == "" {
+			continue
+		}
+		err := initializer.Mailer.Send(mailer.Message{
+			To:      recipient.Email,
+			Subject: subject,
+			Body:    fmt.Sprintf("Hi %s,\n\n%s\n", recipient.Name, interviewSummary(interview)),
+			Attachments: []mailer.Attachment{{
+				FileName:    "invite.ics",
+				ContentType: ical.ContentType + "; method=" + method,
+				Data:        invite,
+			}},
+		})
+		if err != nil {
+			log.Printf("interviews: failed to send invite for interview %d to %s: %v", interview.ID, recipient.Email, err)
+		}
+	}
+}
+
+// NotifyInterviewCandidate tells the candidate about a change to their
+// interview in the app and by email
+func NotifyInterviewCandidate(interview model.Interview, title string) {
+	candidate := interview.Application.User
+	link := PublicURL("/interview/user")
+	notification := model.Notification{
+		UserID: candidate.ID,
+		Type:   model.NotificationInterview,
+		Title:  title,
+		Body:   interviewSummary(interview),
+		Link:   link,
+		Data:   model.JSON(fmt.Sprintf(`{"interview_id":%d,"application_id":%d}`, interview.ID, interview.ApplicationID)),
+	}
+	if err := initializer.DB.Create(&notification).Error; err != nil {
+		log.Printf("interviews: failed to notify user %d: %v", candidate.ID, err)
+	}
+	if candidate.Email == "" {
+		return
+	}
+	body := fmt.Sprintf("Hi %s,\n\n%s\n\n%s\n\nManage your interviews: %s\n", candidate.Name, title, interviewSummary(interview), link)
+	if err := initializer.Mailer.Send(mailer.Message{To: candidate.Email, Subject: title, Body: body}); err != nil {
+		log.Printf("interviews: failed to email user %d: %v", candidate.ID, err)
+	}
+}
+
+// interviewSummary describes an interview in plain text, with times in its
+// time zone
+func interviewSummary(interview model.Interview) string {
+	job := interview.Application.Job
+	lines := []string{fmt.Sprintf("%s for %s at %s (%d minutes)", interview.Title, job.Title, job.Company.Name, interview.DurationMinutes)}
+	switch {
+	case interview.Status == model.InterviewCancelled:
+		line := "This interview has been cancelled."
+		if interview.CancelReason != "" {
+			line += " Reason: " + interview.CancelReason
+		}
+		lines = append(lines, line)
+	case interview.StartsAt != nil:
+		lines = append(lines, "When: "+localTime(*interview.StartsAt, interview.Timezone))
+	default:
+		lines = append(lines, "Choose one of these times:")
+		for _, slot := range interview.Slots {
+			lines = append(lines, "  - "+localTime(slot.StartsAt, interview.Timezone))
+		}
+	}
+	if interview.Location != "" {
+		lines = append(lines, "Where: "+interview.Location)
+	}
+	if interview.VideoURL != "" {
+		lines = append(lines, "Video link: "+interview.VideoURL)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// interviewDescription is the event description: the summary plus the
+// instructions for the candidate
+func interviewDescription(interview model.Interview) string {
+	if interview.Instructions == "" {
+		return interviewSummary(interview)
+	}
+	return interviewSummary(interview) + "\n\n" + interview.Instructions
+}
+
+// localTime formats t in the named zone, falling back to UTC
+func localTime(t time.Time, zone string) string {
+	location, err := time.LoadLocation(zone)
+	if err != nil {
+		location = time.UTC
+	}
+	return t.In(location).Format("Monday 2 January 2006, 15:04 MST") + " (" + location.String() + ")"
+}
+
+// organizerEmail is the address invites are sent from
+func organizerEmail() string {
+	if address, err := mail.ParseAddress(initializer.Config.Mail.From); err == nil {
+		return address.Address
+	}
+	return initializer.Config.Mail.From
+}
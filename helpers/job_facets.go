@@ -0,0 +1,165 @@
+package helpers
+
+import (
+	"strconv"
+
+	"gorm.io/gorm"
+)
+
+// Maximum number of values returned for the skill, location and company facets
+const facetLimit = 25
+
+// FacetCount is the number of matching jobs for one facet value
+type FacetCount struct {
+	Value string `json:"value"`
+	Label string `json:"label,omitempty"`
+	Count int64  `json:"count"`
+}
+
+// JobFacets holds the counts for every filter dimension of the job search
+type JobFacets struct {
+	Skills          []FacetCount `json:"skills"`
+	Locations       []FacetCount `json:"locations"`
+	Companies       []FacetCount `json:"companies"`
+	Categories      []FacetCount `json:"categories"`
+	Seniority       []FacetCount `json:"seniority"`
+	EmploymentTypes []FacetCount `json:"employment_types"`
+	Currencies      []FacetCount `json:"currencies"`
+	Salary          []FacetCount `json:"salary,omitempty"`
+	PostedWithin    []FacetCount `json:"posted_within"`
+}
+
+// Yearly salary buckets, as [lower, upper) bounds; upper 0 means unbounded
+var salaryBuckets = []struct {
+	Label        string
+	Lower, Upper int64
+}{
+	{"0-50000", 0, 50_000},
+	{"50000-100000", 50_000, 100_000},
+	{"100000-150000", 100_000, 150_000},
+	{"150000-200000", 150_000, 200_000},
+	{"200000+", 200_000, 0},
+}
+
+var postedWithinBuckets = []int{1, 7, 30}
+
+// Facets counts matching jobs per value of each dimension. Every dimension is
+// counted with all other filters applied but its own filter removed, so the
+// UI can show how many results selecting another value would give.
+func (f JobFilter) Facets(db *gorm.DB) (JobFacets, error) {
+	var facets JobFacets
+
+	withoutSkills := f
+	withoutSkills.Skills = nil
+	err := withoutSkills.Apply(db.Table("jobs")).
+		Joins("JOIN job_skills ON job_skills.job_id = jobs.id").
+		Joins("JOIN skills ON skills.id = job_skills.skill_id").
+		Where("jobs.deleted_at IS NULL").
+		Select("skills.name AS value, COUNT(DISTINCT jobs.id) AS count").
+		Group("skills.name").Order("count DESC, value").Limit(facetLimit).
+		Scan(&facets.Skills).Error
+	if err != nil {
+		return facets, err
+	}
+
+	withoutLocations := f
+	withoutLocations.Locations = nil
+	err = withoutLocations.Apply(db.Table("jobs")).
+		Where("jobs.deleted_at IS NULL AND jobs.location <> ''").
+		Select("jobs.location AS value, COUNT(*) AS count").
+		Group("jobs.location").Order("count DESC, value").Limit(facetLimit).
+		Scan(&facets.Locations).Error
+	if err != nil {
+		return facets, err
+	}
+
+	withoutCompanies := f
+	withoutCompanies.CompanyIDs = nil
+	err = withoutCompanies.Apply(db.Table("jobs")).
+		Joins("JOIN companies ON companies.id = jobs.company_id").
+		Where("jobs.deleted_at IS NULL").
+		Select("CAST(companies.id AS TEXT) AS value, companies.name AS label, COUNT(*) AS count").
+		Group("companies.id, companies.name").Order("count DESC, label").Limit(facetLimit).
+		Scan(&facets.Companies).Error
+	if err != nil {
+		return facets, err
+	}
+
+	withoutCategories := f
+	withoutCategories.Categories = nil
+	err = withoutCategories.Apply(db.Table("jobs")).
+		Joins("JOIN job_categories ON job_categories.id = jobs.category_id AND job_categories.deleted_at IS NULL").
+		Where("jobs.deleted_at IS NULL").
+		Select("job_categories.slug AS value, job_categories.name AS label, COUNT(*) AS count").
+		Group("job_categories.slug, job_categories.name").Order("count DESC, label").
+		Scan(&facets.Categories).Error
+	if err != nil {
+		return facets, err
+	}
+
+	withoutSeniority := f
+	withoutSeniority.Seniority = nil
+	err = withoutSeniority.Apply(db.Table("jobs")).
+		Where("jobs.deleted_at IS NULL AND jobs.seniority <> ''").
+		Select("jobs.seniority AS value, COUNT(*) AS count").
+		Group("jobs.seniority").Order("count DESC, value").
+		Scan(&facets.Seniority).Error
+	if err != nil {
+		return facets, err
+	}
+
+	withoutEmployment := f
+	withoutEmployment.EmploymentTypes = nil
+	err = withoutEmployment.Apply(db.Table("jobs")).
+		Where("jobs.deleted_at IS NULL AND jobs.employment_type <> ''").
+		Select("jobs.employment_type AS value, COUNT(*) AS count").
+		Group("jobs.employment_type").Order("count DESC, value").
+		Scan(&facets.EmploymentTypes).Error
+	if err != nil {
+		return facets, err
+	}
+
+	withoutSalary := f
+	withoutSalary.Currency, withoutSalary.SalaryMin, withoutSalary.SalaryMax = "", nil, nil
+	err = withoutSalary.Apply(db.Table("jobs")).
+		Where("jobs.deleted_at IS NULL AND jobs.salary_currency <> '' AND jobs.salary_hidden = ?", false).
+		Select("jobs.salary_currency AS value, COUNT(*) AS count").
+		Group("jobs.salary_currency").Order("count DESC, value").
+		Scan(&facets.Currencies).Error
+	if err != nil {
+		return facets, err
+	}
+
+	// Salary buckets only make sense within a single currency
+	if f.Currency != "" {
+		withCurrency := withoutSalary
+		withCurrency.Currency = f.Currency
+		for _, bucket := range salaryBuckets {
+			query := withCurrency.Apply(db.Table("jobs")).
+				Where("jobs.deleted_at IS NULL AND jobs.salary_hidden = ?", false).
+				Where("COALESCE(jobs.salary_annual_max, jobs.salary_annual_min) >= ?", bucket.Lower)
+			if bucket.Upper > 0 {
+				query = query.Where("COALESCE(jobs.salary_annual_max, jobs.salary_annual_min) < ?", bucket.Upper)
+			}
+			var count int64
+			if err := query.Count(&count).Error; err != nil {
+				return facets, err
+			}
+			facets.Salary = append(facets.Salary, FacetCount{Value: bucket.Label, Count: count})
+		}
+	}
+
+	withoutPosted := f
+	withoutPosted.PostedWithinDays = 0
+	for _, days := range postedWithinBuckets {
+		scoped := withoutPosted
+		scoped.PostedWithinDays = days
+		var count int64
+		if err := scoped.Apply(db.Table("jobs")).Where("jobs.deleted_at IS NULL").Count(&count).Error; err != nil {
+			return facets, err
+		}
+		facets.PostedWithin = append(facets.PostedWithin, FacetCount{Value: strconv.Itoa(days), Count: count})
+	}
+
+	return facets, nil
+}
@@ -0,0 +1,486 @@
+package helpers
+
+import (
+	"errors"
+	"html"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sahilq312/workly/geo"
+	"github.com/sahilq312/workly/model"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Sort orders accepted by JobFilter
+const (
+	SortRelevance  = "relevance"
+	SortNewest     = "newest"
+	SortSalaryDesc = "salary_desc"
+	SortSalaryAsc  = "salary_asc"
+	SortDistance   = "distance"
+)
+
+// Default radius for searches around a place
+const defaultRadiusKm = 50
+
+// JobFilter is the set of filters accepted by the public job listing
+type JobFilter struct {
+	Title    string `json:"title,omitempty"`
+	Location string `json:"location,omitempty"`
+	Search   string `json:"search,omitempty"`
+	// Treat every search term as a prefix, for typeahead
+	Prefix bool `json:"prefix,omitempty"`
+
+	// Yearly pay bounds in Currency; a job matches when its range overlaps them
+	SalaryMin *int64 `json:"salary_min,omitempty"`
+	SalaryMax *int64 `json:"salary_max,omitempty"`
+	Currency  string `json:"currency,omitempty"`
+
+	// Skill names; SkillMode "all" requires every skill, "any" (default) at least one
+	Skills    []string `json:"skills,omitempty"`
+	SkillMode string   `json:"skill_mode,omitempty"`
+	// Exact (case-insensitive) locations, as returned by the location facet
+	Locations  []string `json:"locations,omitempty"`
+	CompanyIDs []uint   `json:"company_ids,omitempty"`
+	// Only jobs published in the last N days
+	PostedWithinDays int `json:"posted_within_days,omitempty"`
+
+	// Category slugs, seniority levels and employment types; a job matches any listed value
+	Categories      []string `json:"categories,omitempty"`
+	Seniority       []string `json:"seniority,omitempty"`
+	EmploymentTypes []string `json:"employment_types,omitempty"`
+
+	WorkModes []string `json:"work_modes,omitempty"`
+	// Remote jobs that can be done from this ISO country
+	RemoteCountry string `json:"remote_country,omitempty"`
+	// Jobs with a location within RadiusKm of a point. Near keeps the place
+	// name the point was geocoded from; IncludeRemote also matches remote jobs.
+	Near          string   `json:"near,omitempty"`
+	Latitude      *float64 `json:"latitude,omitempty"`
+	Longitude     *float64 `json:"longitude,omitempty"`
+	RadiusKm      float64  `json:"radius_km,omitempty"`
+	IncludeRemote bool     `json:"include_remote,omitempty"`
+
+	Sort string `json:"sort,omitempty"`
+}
+
+// Skill matching modes
+const (
+	SkillModeAny = "any"
+	SkillModeAll = "all"
+)
+
+// ParseJobFilter reads a JobFilter from the query string
+func ParseJobFilter(c *gin.Context) (JobFilter, error) {
+	return ParseJobFilterQuery(c.Request.URL.Query())
+}
+
+// ParseJobFilterQuery reads a JobFilter from query parameters, as used by
+// GET /job/ and stored by saved searches
+func ParseJobFilterQuery(q url.Values) (JobFilter, error) {
+	f := JobFilter{
+		Title:    q.Get("title"),
+		Location: q.Get("location"),
+		Search:   q.Get("search"),
+		Currency: strings.ToUpper(q.Get("currency")),
+		Sort:     q.Get("sort"),
+	}
+	if prefix := q.Get("prefix"); prefix != "" {
+		var err error
+		if f.Prefix, err = strconv.ParseBool(prefix); err != nil {
+			return f, errors.New("prefix must be true or false")
+		}
+	}
+
+	f.Skills = queryList(q, "skills")
+	f.SkillMode = strings.ToLower(q.Get("skill_mode"))
+	f.Locations = queryList(q, "locations")
+	for _, value := range queryList(q, "company_ids") {
+		id, err := strconv.ParseUint(value, 10, 32)
+		if err != nil {
+			return f, errors.New("company_ids must be numeric")
+		}
+		f.CompanyIDs = append(f.CompanyIDs, uint(id))
+	}
+
+	for _, category := range queryList(q, "category") {
+		f.Categories = append(f.Categories, strings.ToLower(category))
+	}
+	for _, level := range queryList(q, "seniority") {
+		f.Seniority = append(f.Seniority, strings.ToLower(level))
+	}
+	for _, kind := range queryList(q, "employment_type") {
+		f.EmploymentTypes = append(f.EmploymentTypes, strings.ToLower(kind))
+	}
+	for _, mode := range queryList(q, "work_mode") {
+		f.WorkModes = append(f.WorkModes, strings.ToLower(mode))
+	}
+	f.RemoteCountry = strings.ToUpper(q.Get("remote_country"))
+	if err := f.parseNear(q); err != nil {
+		return f, err
+	}
+
+	days, err := queryInt64(q, "posted_within")
+	if err != nil {
+		return f, errors.New("posted_within must be a number of days")
+	}
+	if days != nil {
+		f.PostedWithinDays = int(*days)
+	}
+	if f.SalaryMin, err = queryInt64(q, "salary_min"); err != nil {
+		return f, err
+	}
+	if f.SalaryMax, err = queryInt64(q, "salary_max"); err != nil {
+		return f, err
+	}
+	return f, f.Validate()
+}
+
+// Validate checks that the filter values are consistent
+func (f JobFilter) Validate() error {
+	if (f.SalaryMin != nil || f.SalaryMax != nil) && len(f.Currency) != 3 {
+		return errors.New("salary filters require a 3-letter currency")
+	}
+	if f.SalaryMin != nil && f.SalaryMax != nil && *f.SalaryMin > *f.SalaryMax {
+		return errors.New("salary_min cannot exceed salary_max")
+	}
+	for _, mode := range f.WorkModes {
+		if mode != model.WorkOnsite && mode != model.WorkHybrid && mode != model.WorkRemote {
+			return errors.New("work_mode must be onsite, hybrid or remote")
+		}
+	}
+	for _, level := range f.Seniority {
+		if !ContainsString(model.SeniorityLevels, level) {
+			return errors.New("seniority must be one of " + strings.Join(model.SeniorityLevels, ", "))
+		}
+	}
+	for _, kind := range f.EmploymentTypes {
+		if !ContainsString(model.EmploymentTypes, kind) {
+			return errors.New("employment_type must be one of " + strings.Join(model.EmploymentTypes, ", "))
+		}
+	}
+	if f.RemoteCountry != "" && len(f.RemoteCountry) != 2 {
+		return errors.New("remote_country must be a 2-letter ISO code")
+	}
+	if f.RadiusKm < 0 || f.RadiusKm > 20000 {
+		return errors.New("radius_km must be between 0 and 20000")
+	}
+	switch f.SkillMode {
+	case "", SkillModeAny, SkillModeAll:
+	default:
+		return errors.New("skill_mode must be any or all")
+	}
+	switch f.Sort {
+	case "", SortNewest:
+	case SortSalaryDesc, SortSalaryAsc:
+		// Amounts in different currencies cannot be compared
+		if len(f.Currency) != 3 {
+			return errors.New("salary sorts require a 3-letter currency")
+		}
+	case SortRelevance:
+		if f.Search == "" {
+			return errors.New("sort=relevance requires a search")
+		}
+	case SortDistance:
+		if f.Latitude == nil {
+			return errors.New("sort=distance requires near or latitude/longitude")
+		}
+	default:
+		return errors.New("sort must be one of relevance, newest, salary_desc, salary_asc, distance")
+	}
+	return nil
+}
+
+// Apply restricts a query on jobs to open postings matching the filter
+func (f JobFilter) Apply(query *gorm.DB) *gorm.DB {
+	query = query.Where("jobs.status = ? AND (jobs.expires_at IS NULL OR jobs.expires_at > NOW())", model.JobPublished)
+	if f.Title != "" {
+		query = query.Where("jobs.title ILIKE ?", "%"+f.Title+"%")
+	}
+	if f.Location != "" {
+		query = query.Where("jobs.location ILIKE ?", "%"+f.Location+"%")
+	}
+	if tsQuery, ok := f.tsQuery(); ok {
+		query = query.Where("jobs.search_vector @@ ?", tsQuery)
+	}
+	if len(f.Skills) > 0 {
+		// Skills match by normalized name or alias, so "golang" finds Go jobs
+		keys := make([]string, len(f.Skills))
+		for i, name := range f.Skills {
+			keys[i] = model.SkillKey(name)
+		}
+		matching := "SELECT job_skills.job_id FROM job_skills JOIN skills ON skills.id = job_skills.skill_id" +
+			" WHERE skills.key IN ? OR skills.id IN (SELECT skill_id FROM skill_aliases WHERE key IN ? AND deleted_at IS NULL)"
+		if f.SkillMode == SkillModeAll {
+			for _, key := range uniqueStrings(keys) {
+				query = query.Where("jobs.id IN ("+matching+")", []string{key}, []string{key})
+			}
+		} else {
+			query = query.Where("jobs.id IN ("+matching+")", keys, keys)
+		}
+	}
+	if len(f.Locations) > 0 {
+		locations := make([]string, len(f.Locations))
+		for i, location := range f.Locations {
+			locations[i] = strings.ToLower(location)
+		}
+		query = query.Where("LOWER(jobs.location) IN ?", locations)
+	}
+	if len(f.CompanyIDs) > 0 {
+		query = query.Where("jobs.company_id IN ?", f.CompanyIDs)
+	}
+	if f.PostedWithinDays > 0 {
+		query = query.Where("jobs.published_at >= ?", time.Now().AddDate(0, 0, -f.PostedWithinDays))
+	}
+	if len(f.Categories) > 0 {
+		query = query.Where("jobs.category_id IN (SELECT id FROM job_categories WHERE slug IN ? AND deleted_at IS NULL)", f.Categories)
+	}
+	if len(f.Seniority) > 0 {
+		query = query.Where("jobs.seniority IN ?", f.Seniority)
+	}
+	if len(f.EmploymentTypes) > 0 {
+		query = query.Where("jobs.employment_type IN ?", f.EmploymentTypes)
+	}
+	if len(f.WorkModes) > 0 {
+		query = query.Where("jobs.work_mode IN ?", f.WorkModes)
+	}
+	if f.RemoteCountry != "" {
+		query = query.Where("jobs.work_mode = ? AND (jobs.remote_countries = '' OR ? = ANY(string_to_array(jobs.remote_countries, ',')))", model.WorkRemote, f.RemoteCountry)
+	}
+	if f.Latitude != nil && f.Longitude != nil {
+		within := "EXISTS (SELECT 1 FROM job_locations WHERE job_locations.job_id = jobs.id AND job_locations.deleted_at IS NULL" +
+			" AND job_locations.latitude BETWEEN ? AND ? AND " + distanceSQL + " <= ?)"
+		latDelta := f.RadiusKm / 111.0
+		args := []interface{}{*f.Latitude - latDelta, *f.Latitude + latDelta, *f.Latitude, *f.Latitude, *f.Longitude, f.RadiusKm}
+		if f.IncludeRemote {
+			query = query.Where("("+within+" OR jobs.work_mode = ?)", append(args, model.WorkRemote)...)
+		} else {
+			query = query.Where(within, args...)
+		}
+	}
+	if f.Currency != "" || f.SalaryMin != nil || f.SalaryMax != nil {
+		// Hidden salaries never take part in salary or currency filtering so
+		// they cannot be probed
+		query = query.Where("jobs.salary_hidden = ?", false)
+	}
+	if f.Currency != "" {
+		query = query.Where("jobs.salary_currency = ?", f.Currency)
+	}
+	if f.SalaryMin != nil {
+		query = query.Where("COALESCE(jobs.salary_annual_max, jobs.salary_annual_min) >= ?", *f.SalaryMin)
+	}
+	if f.SalaryMax != nil {
+		query = query.Where("COALESCE(jobs.salary_annual_min, jobs.salary_annual_max) <= ?", *f.SalaryMax)
+	}
+	return query
+}
+
+// Order adds the requested sort order to a query on jobs
+func (f JobFilter) Order(query *gorm.DB) *gorm.DB {
+	sort := f.Sort
+	if sort == "" {
+		sort = SortNewest
+		if _, ok := f.tsQuery(); ok {
+			sort = SortRelevance
+		}
+	}
+
+	switch sort {
+	case SortDistance:
+		if f.Latitude == nil || f.Longitude == nil {
+			return query.Order("jobs.created_at DESC, jobs.id DESC")
+		}
+		return query.Order(clause.OrderBy{Expression: clause.Expr{
+			SQL:  "(SELECT MIN(" + distanceSQL + ") FROM job_locations WHERE job_locations.job_id = jobs.id AND job_locations.deleted_at IS NULL) ASC NULLS LAST, jobs.id DESC",
+			Vars: []interface{}{*f.Latitude, *f.Latitude, *f.Longitude},
+		}})
+	case SortRelevance:
+		tsQuery, ok := f.tsQuery()
+		if !ok {
+			return query.Order("jobs.created_at DESC, jobs.id DESC")
+		}
+		return query.Order(clause.OrderBy{Expression: clause.Expr{
+			SQL:  "ts_rank_cd(jobs.search_vector, ?) DESC, jobs.id DESC",
+			Vars: []interface{}{tsQuery},
+		}})
+	// Hidden salaries sort last and by ID only, so their position
+	// says nothing about the amounts
+	case SortSalaryDesc:
+		return query.Order("CASE WHEN jobs.salary_hidden THEN NULL ELSE COALESCE(jobs.salary_annual_max, jobs.salary_annual_min) END DESC NULLS LAST, jobs.id DESC")
+	case SortSalaryAsc:
+		return query.Order("CASE WHEN jobs.salary_hidden THEN NULL ELSE COALESCE(jobs.salary_annual_min, jobs.salary_annual_max) END ASC NULLS LAST, jobs.id DESC")
+	default:
+		return query.Order("jobs.created_at DESC, jobs.id DESC")
+	}
+}
+
+// tsQuery builds the Postgres text-search query for Search. Plain searches
+// accept web-search syntax ("quoted phrases", -excluded, or); prefix searches
+// match every word as a prefix.
+func (f JobFilter) tsQuery() (clause.Expr, bool) {
+	if strings.TrimSpace(f.Search) == "" {
+		return clause.Expr{}, false
+	}
+	if !f.Prefix {
+		return gorm.Expr("websearch_to_tsquery('english', ?)", f.Search), true
+	}
+
+	terms := strings.FieldsFunc(f.Search, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	if len(terms) == 0 {
+		return clause.Expr{}, false
+	}
+	for i, term := range terms {
+		terms[i] = term + ":*"
+	}
+	return gorm.Expr("to_tsquery('english', ?)", strings.Join(terms, " & ")), true
+}
+
+// JobHighlight holds HTML-escaped search snippets with matches marked with
+// <mark> tags
+type JobHighlight struct {
+	Title   string `json:"title"`
+	Snippet string `json:"snippet"`
+}
+
+// Highlights returns marked-up title and description snippets for the given
+// jobs, or nil when the filter has no search
+func (f JobFilter) Highlights(db *gorm.DB, ids []uint) (map[uint]JobHighlight, error) {
+	tsQuery, ok := f.tsQuery()
+	if !ok || len(ids) == 0 {
+		return nil, nil
+	}
+
+	// Matches are marked with private-use characters, stripped from the job
+	// text first, so the text can be escaped before they become <mark> tags
+	const (
+		titleOptions = "HighlightAll=true, StartSel=" + highlightStart + ", StopSel=" + highlightStop
+		options      = "StartSel=" + highlightStart + ", StopSel=" + highlightStop + ", MaxWords=35, MinWords=15, MaxFragments=2, FragmentDelimiter=\" … \""
+	)
+	var rows []struct {
+		ID      uint
+		Title   string
+		Snippet string
+	}
+	err := db.Model(&model.Job{}).
+		Select("jobs.id, ts_headline('english', translate(jobs.title, ?, ''), ?, ?) AS title, ts_headline('english', translate(jobs.description, ?, ''), ?, ?) AS snippet",
+			highlightStart+highlightStop, tsQuery, titleOptions, highlightStart+highlightStop, tsQuery, options).
+		Where("jobs.id IN ?", ids).
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	highlights := make(map[uint]JobHighlight, len(rows))
+	for _, row := range rows {
+		highlights[row.ID] = JobHighlight{Title: markHighlights(row.Title), Snippet: markHighlights(row.Snippet)}
+	}
+	return highlights, nil
+}
+
+// Markers ts_headline puts around matches
+const (
+	highlightStart = "\uE000"
+	highlightStop  = "\uE001"
+)
+
+// markHighlights escapes a headline and turns its markers into <mark> tags
+func markHighlights(headline string) string {
+	return strings.NewReplacer(highlightStart, "<mark>", highlightStop, "</mark>").Replace(html.EscapeString(headline))
+}
+
+// distanceSQL is the haversine distance in km from a job location to a point,
+// taking the point's latitude twice and then its longitude as arguments
+const distanceSQL = "2 * 6371 * ASIN(SQRT(POWER(SIN(RADIANS(job_locations.latitude - ?) / 2), 2)" +
+	" + COS(RADIANS(?)) * COS(RADIANS(job_locations.latitude)) * POWER(SIN(RADIANS(job_locations.longitude - ?) / 2), 2)))"
+
+// parseNear reads a radius search given either as a place name (near) or as
+// latitude and longitude
+func (f *JobFilter) parseNear(q url.Values) error {
+	f.Near = q.Get("near")
+	if f.Near != "" {
+		place, ok := geo.Lookup(f.Near)
+		if !ok {
+			return errors.New("unknown place in near: " + f.Near)
+		}
+		f.Latitude, f.Longitude = &place.Latitude, &place.Longitude
+	} else if lat, lon := q.Get("latitude"), q.Get("longitude"); lat != "" || lon != "" {
+		latitude, err1 := strconv.ParseFloat(lat, 64)
+		longitude, err2 := strconv.ParseFloat(lon, 64)
+		if err1 != nil || err2 != nil || latitude < -90 || latitude > 90 || longitude < -180 || longitude > 180 {
+			return errors.New("latitude and longitude must both be valid coordinates")
+		}
+		f.Latitude, f.Longitude = &latitude, &longitude
+	}
+
+	if radius := q.Get("radius_km"); radius != "" {
+		r, err := strconv.ParseFloat(radius, 64)
+		if err != nil {
+			return errors.New("radius_km must be a number")
+		}
+		f.RadiusKm = r
+	}
+	if f.Latitude != nil && f.RadiusKm == 0 {
+		f.RadiusKm = defaultRadiusKm
+	}
+	if includeRemote := q.Get("include_remote"); includeRemote != "" {
+		var err error
+		if f.IncludeRemote, err = strconv.ParseBool(includeRemote); err != nil {
+			return errors.New("include_remote must be true or false")
+		}
+	}
+	return nil
+}
+
+// queryList reads a repeated query parameter, also splitting comma-separated values
+func queryList(q url.Values, key string) []string {
+	var values []string
+	for _, raw := range q[key] {
+		for _, value := range strings.Split(raw, ",") {
+			if value = strings.TrimSpace(value); value != "" {
+				values = append(values, value)
+			}
+		}
+	}
+	return values
+}
+
+// ContainsString reports whether value is one of values
+func ContainsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+func uniqueStrings(values []string) []string {
+	seen := make(map[string]bool, len(values))
+	unique := values[:0:0]
+	for _, value := range values {
+		if !seen[value] {
+			seen[value] = true
+			unique = append(unique, value)
+		}
+	}
+	return unique
+}
+
+func queryInt64(q url.Values, key string) (*int64, error) {
+	value := q.Get(key)
+	if value == "" {
+		return nil, nil
+	}
+	n, err := strconv.ParseInt(value, 10, 64)
+	if err != nil || n < 0 {
+		return nil, errors.New(key + " must be a non-negative integer")
+	}
+	return &n, nil
+}
@@ -0,0 +1,324 @@
+package helpers
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/sahilq312/workly/model"
+)
+
+// Import and export formats
+const (
+	FormatCSV  = "csv"
+	FormatJSON = "json"
+)
+
+// JobImportColumns are the CSV columns read by imports and written by exports.
+// Lists (locations, remote_countries, skills) are separated by listSeparator.
+var JobImportColumns = []string{
+	"id", "external_ref", "title", "description", "status",
+	"work_mode", "location", "locations", "remote_countries",
+	"salary", "salary_min", "salary_max", "salary_currency", "salary_period", "salary_equity", "salary_hidden",
+	"skills", "category", "seniority", "employment_type", "expires_at",
+}
+
+const listSeparator = "|"
+
+// JobImportRow is one job of a bulk import or export. Rows are matched to
+// existing jobs by ExternalRef, or by ID when no reference is given.
+type JobImportRow struct {
+	ID              uint                `json:"id,omitempty"`
+	ExternalRef     string              `json:"external_ref"`
+	Title           string              `json:"title"`
+	Description     string              `json:"description"`
+	Status          string              `json:"status"`
+	WorkMode        string              `json:"work_mode"`
+	Location        string              `json:"location"`
+	Locations       []LocationInput     `json:"locations"`
+	RemoteCountries []string            `json:"remote_countries"`
+	Salary          string              `json:"salary"`
+	Compensation    *model.Compensation `json:"compensation"`
+	Skills          []string            `json:"skills"`
+	Category        string              `json:"category"`
+	Seniority       string              `json:"seniority"`
+	EmploymentType  string              `json:"employment_type"`
+	ExpiresAt       *time.Time          `json:"expires_at"`
+}
+
+// ParsedImportRow is a row read from an import file. Line is the CSV line
+// number or the 1-based position in a JSON array; Errors holds problems
+// found while reading the row.
+type ParsedImportRow struct {
+	Line   int
+	Row    JobImportRow
+	Errors []string
+}
+
+// ParseJobImport reads import rows in the given format. Malformed rows are
+// reported on the row; an error is only returned when the file as a whole
+// cannot be read.
+func ParseJobImport(r io.Reader, format string) ([]ParsedImportRow, error) {
+	switch format {
+	case FormatCSV:
+		return parseJobCSV(r)
+	case FormatJSON:
+		return parseJobJSON(r)
+	}
+	return nil, fmt.Errorf("unsupported format %q", format)
+}
+
+func parseJobJSON(r io.Reader) ([]ParsedImportRow, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	// Accept a bare array or {"jobs": [...]}
+	var items []json.RawMessage
+	if trimmed := bytes.TrimSpace(data); len(trimmed) > 0 && trimmed[0] == '{' {
+		var wrapped struct {
+			Jobs []json.RawMessage `json:"jobs"`
+		}
+		if err := json.Unmarshal(trimmed, &wrapped); err != nil {
+			return nil, fmt.Errorf("invalid JSON: %w", err)
+		}
+		items = wrapped.Jobs
+	} else if err := json.Unmarshal(trimmed, &items); err != nil {
+		return nil, fmt.Errorf("invalid JSON: %w", err)
+	}
+
+	rows := make([]ParsedImportRow, len(items))
+	for i, item := range items {
+		rows[i].Line = i + 1
+		if err := json.Unmarshal(item, &rows[i].Row); err != nil {
+			rows[i].Errors = append(rows[i].Errors, "invalid job object: "+err.Error())
+		}
+	}
+	return rows, nil
+}
+
+func parseJobCSV(r io.Reader) ([]ParsedImportRow, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err == io.EOF {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("invalid CSV header: %w", err)
+	}
+	columns := map[string]int{}
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))] = i
+	}
+	if _, ok := columns["title"]; !ok {
+		return nil, errors.New("CSV header must include a title column")
+	}
+
+	var rows []ParsedImportRow
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			var parseErr *csv.ParseError
+			if errors.As(err, &parseErr) {
+				rows = append(rows, ParsedImportRow{Line: parseErr.Line, Errors: []string{parseErr.Err.Error()}})
+				continue
+			}
+			return nil, err
+		}
+		line, _ := reader.FieldPos(0)
+		rows = append(rows, parseJobRecord(record, columns, line))
+	}
+	return rows, nil
+}
+
+// parseJobRecord maps one CSV record onto a row, noting unreadable values
+func parseJobRecord(record []string, columns map[string]int, line int) ParsedImportRow {
+	parsed := ParsedImportRow{Line: line}
+	get := func(name string) string {
+		if i, ok := columns[name]; ok && i < len(record) {
+			return strings.TrimSpace(record[i])
+		}
+		return ""
+	}
+	fail := func(format string, args ...interface{}) {
+		parsed.Errors = append(parsed.Errors, fmt.Sprintf(format, args...))
+	}
+
+	row := &parsed.Row
+	if id := get("id"); id != "" {
+		n, err := strconv.ParseUint(id, 10, 32)
+		if err != nil {
+			fail("id must be a job ID")
+		}
+		row.ID = uint(n)
+	}
+	row.ExternalRef = get("external_ref")
+	row.Title = get("title")
+	row.Description = get("description")
+	row.Status = get("status")
+	row.WorkMode = get("work_mode")
+	row.Location = get("location")
+	for _, label := range splitList(get("locations")) {
+		row.Locations = append(row.Locations, parseLocationLabel(label))
+	}
+	row.RemoteCountries = splitList(get("remote_countries"))
+	row.Salary = get("salary")
+	row.Skills = splitList(get("skills"))
+	row.Category = get("category")
+	row.Seniority = get("seniority")
+	row.EmploymentType = get("employment_type")
+	if expires := get("expires_at"); expires != "" {
+		t, err := time.Parse(time.RFC3339, expires)
+		if err != nil {
+			fail("expires_at must be an RFC 3339 timestamp")
+		} else {
+			row.ExpiresAt = &t
+		}
+	}
+
+	// Structured pay takes precedence over the salary text when any part is given
+	comp := model.Compensation{
+		Currency: strings.ToUpper(get("salary_currency")),
+		Period:   strings.ToLower(get("salary_period")),
+	}
+	for _, amount := range []struct {
+		column string
+		dest   **int64
+	}{{"salary_min", &comp.Min}, {"salary_max", &comp.Max}} {
+		if value := get(amount.column); value != "" {
+			n, err := strconv.ParseInt(value, 10, 64)
+			if err != nil {
+				fail("%s must be a whole number", amount.column)
+				continue
+			}
+			*amount.dest = &n
+		}
+	}
+	for _, flag := range []struct {
+		column string
+		dest   *bool
+	}{{"salary_equity", &comp.Equity}, {"salary_hidden", &comp.Hidden}} {
+		if value := get(flag.column); value != "" {
+			b, err := strconv.ParseBool(value)
+			if err != nil {
+				fail("%s must be true or false", flag.column)
+				continue
+			}
+			*flag.dest = b
+		}
+	}
+	if comp.Min != nil || comp.Max != nil || comp.Currency != "" || comp.Period != "" {
+		row.Compensation = &comp
+	}
+	return parsed
+}
+
+// WriteJobsCSV writes jobs in the import format. Jobs need Skills, Locations
+// and Category loaded.
+func WriteJobsCSV(w io.Writer, jobs []model.Job) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write(JobImportColumns); err != nil {
+		return err
+	}
+	for _, job := range jobs {
+		row := JobExportRow(job)
+		comp := model.Compensation{}
+		if row.Compensation != nil {
+			comp = *row.Compensation
+		}
+		var locations []string
+		for _, location := range job.Locations {
+			locations = append(locations, location.Label())
+		}
+		record := []string{
+			strconv.FormatUint(uint64(row.ID), 10), row.ExternalRef, row.Title, row.Description, row.Status,
+			row.WorkMode, row.Location, strings.Join(locations, listSeparator), strings.Join(row.RemoteCountries, listSeparator),
+			row.Salary, formatAmount(comp.Min), formatAmount(comp.Max), comp.Currency, comp.Period,
+			strconv.FormatBool(comp.Equity), strconv.FormatBool(comp.Hidden),
+			strings.Join(row.Skills, listSeparator), row.Category, row.Seniority, row.EmploymentType, "",
+		}
+		if row.ExpiresAt != nil {
+			record[len(record)-1] = row.ExpiresAt.UTC().Format(time.RFC3339)
+		}
+		if err := writer.Write(record); err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+// JobExportRow converts a job with Skills, Locations and Category loaded into
+// a row that imports back unchanged
+func JobExportRow(job model.Job) JobImportRow {
+	row := JobImportRow{
+		ID:              job.ID,
+		ExternalRef:     job.ExternalRef,
+		Title:           job.Title,
+		Description:     job.Description,
+		Status:          job.Status,
+		WorkMode:        job.WorkMode,
+		Location:        job.Location,
+		RemoteCountries: []string(job.RemoteCountries),
+		Salary:          job.Salary,
+		Seniority:       job.Seniority,
+		EmploymentType:  job.EmploymentType,
+		ExpiresAt:       job.ExpiresAt,
+	}
+	for _, location := range job.Locations {
+		row.Locations = append(row.Locations, LocationInput{
+			City: location.City, Region: location.Region, Country: location.Country,
+			Latitude: location.Latitude, Longitude: location.Longitude,
+		})
+	}
+	for _, skill := range job.Skills {
+		row.Skills = append(row.Skills, skill.Name)
+	}
+	if job.Category != nil {
+		row.Category = job.Category.Slug
+	}
+	if !job.Compensation.IsZero() {
+		comp := job.Compensation
+		row.Compensation = &comp
+	}
+	return row
+}
+
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, listSeparator) {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// parseLocationLabel reads "Berlin, DE" or "Berlin" as written by exports
+func parseLocationLabel(label string) LocationInput {
+	if i := strings.LastIndex(label, ","); i >= 0 {
+		if country := strings.TrimSpace(label[i+1:]); len(country) == 2 {
+			return LocationInput{City: strings.TrimSpace(label[:i]), Country: strings.ToUpper(country)}
+		}
+	}
+	return LocationInput{City: label}
+}
+
+func formatAmount(amount *int64) string {
+	if amount == nil {
+		return ""
+	}
+	return strconv.FormatInt(*amount, 10)
+}
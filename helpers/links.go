@@ -0,0 +1,24 @@
+package helpers
+
+import (
+	"strings"
+
+	"github.com/sahilq312/workly/initializer"
+	"github.com/sahilq312/workly/model"
+	"github.com/sahilq312/workly/seo"
+)
+
+// PublicURL joins a path onto the externally reachable base URL
+func PublicURL(path string) string {
+	return strings.TrimSuffix(initializer.Config.Server.PublicURL, "/") + path
+}
+
+// JobURL is the public page of a job. The job needs Company loaded.
+func JobURL(job model.Job) string {
+	return PublicURL(seo.JobPath(job))
+}
+
+// CompanyURL is the public page of a company
+func CompanyURL(company model.Company) string {
+	return PublicURL(seo.CompanyPath(company))
+}
@@ -0,0 +1,132 @@
+package helpers
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/sahilq312/workly/geo"
+	"github.com/sahilq312/workly/model"
+)
+
+// LocationInput is a job location as submitted by a company
+type LocationInput struct {
+	City      string   `json:"city"`
+	Region    string   `json:"region"`
+	Country   string   `json:"country"`
+	Latitude  *float64 `json:"latitude"`
+	Longitude *float64 `json:"longitude"`
+}
+
+// JobPlacement is the validated where-and-how of a job
+type JobPlacement struct {
+	WorkMode        string
+	RemoteCountries model.StringList
+	Locations       []model.JobLocation
+	// Display text kept in the legacy Job.Location column
+	Label string
+}
+
+// ResolveJobPlacement validates the work mode and locations of a job and
+// geocodes cities without coordinates using the offline gazetteer. When no
+// structured locations are given, legacy free-form text is geocoded instead.
+func ResolveJobPlacement(workMode string, inputs []LocationInput, legacy string, remoteCountries []string) (JobPlacement, error) {
+	placement := JobPlacement{WorkMode: strings.ToLower(strings.TrimSpace(workMode))}
+	legacy = strings.TrimSpace(legacy)
+
+	if len(inputs) == 0 && legacy != "" {
+		if strings.EqualFold(legacy, "remote") {
+			if placement.WorkMode == "" {
+				placement.WorkMode = model.WorkRemote
+			}
+		} else {
+			input := LocationInput{City: legacy}
+			if place, ok := geo.Lookup(legacy); ok {
+				input = LocationInput{City: place.City, Country: place.Country}
+			}
+			inputs = append(inputs, input)
+		}
+	}
+	if placement.WorkMode == "" {
+		placement.WorkMode = model.WorkOnsite
+	}
+
+	var errs []error
+	switch placement.WorkMode {
+	case model.WorkOnsite, model.WorkHybrid:
+		if len(inputs) == 0 {
+			errs = append(errs, fmt.Errorf("%s jobs need at least one location", placement.WorkMode))
+		}
+	case model.WorkRemote:
+	default:
+		errs = append(errs, fmt.Errorf("work_mode must be onsite, hybrid or remote, got %q", workMode))
+	}
+
+	for _, code := range remoteCountries {
+		code = strings.ToUpper(strings.TrimSpace(code))
+		if len(code) != 2 {
+			errs = append(errs, fmt.Errorf("remote country %q must be a 2-letter ISO code", code))
+			continue
+		}
+		placement.RemoteCountries = append(placement.RemoteCountries, code)
+	}
+	if len(placement.RemoteCountries) > 0 && placement.WorkMode != model.WorkRemote {
+		errs = append(errs, errors.New("remote_countries only apply to remote jobs"))
+	}
+
+	var labels []string
+	for i, input := range inputs {
+		location, err := resolveLocation(input)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("location %d: %w", i+1, err))
+			continue
+		}
+		placement.Locations = append(placement.Locations, location)
+		labels = append(labels, location.Label())
+	}
+	if placement.WorkMode == model.WorkRemote {
+		labels = append([]string{"Remote"}, labels...)
+	}
+	placement.Label = strings.Join(labels, "; ")
+
+	return placement, errors.Join(errs...)
+}
+
+func resolveLocation(input LocationInput) (model.JobLocation, error) {
+	location := model.JobLocation{
+		City:      strings.TrimSpace(input.City),
+		Region:    strings.TrimSpace(input.Region),
+		Country:   strings.ToUpper(strings.TrimSpace(input.Country)),
+		Latitude:  input.Latitude,
+		Longitude: input.Longitude,
+	}
+	if location.City == "" && location.Country == "" {
+		return location, errors.New("city or country is required")
+	}
+	if location.Country != "" && len(location.Country) != 2 {
+		return location, fmt.Errorf("country %q must be a 2-letter ISO code", location.Country)
+	}
+	if (location.Latitude == nil) != (location.Longitude == nil) {
+		return location, errors.New("latitude and longitude must be given together")
+	}
+	if location.Latitude != nil {
+		if *location.Latitude < -90 || *location.Latitude > 90 || *location.Longitude < -180 || *location.Longitude > 180 {
+			return location, errors.New("coordinates are out of range")
+		}
+		return location, nil
+	}
+
+	// Geocode offline; unknown cities are kept but cannot match radius searches
+	if location.City != "" {
+		if place, ok := geo.LookupIn(location.City, location.Country); ok {
+			location.City = place.City
+			location.Country = place.Country
+			if location.Region == "" {
+				location.Region = place.Region
+			}
+			lat, lon := place.Latitude, place.Longitude
+			location.Latitude, location.Longitude = &lat, &lon
+		}
+	}
+	return location, nil
+}
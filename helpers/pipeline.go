@@ -0,0 +1,109 @@
+package helpers
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/sahilq312/workly/model"
+	"gorm.io/gorm"
+)
+
+// Most stages a pipeline may have
+const maxPipelineStages = 20
+
+// StageInput is a pipeline stage as sent by a company. ID keeps an existing
+// stage of the job, so applications in it stay where they are.
+type StageInput struct {
+	ID       uint   `json:"id"`
+	Name     string `json:"name"`
+	Category string `json:"category"`
+}
+
+// BuildPipelineStages validates stage inputs and converts them to models in
+// the order given. A pipeline needs an applied stage for new applications
+// to land in.
+func BuildPipelineStages(inputs []StageInput) ([]model.PipelineStage, error) {
+	if len(inputs) == 0 {
+		return nil, errors.New("a pipeline needs at least one stage")
+	}
+	if len(inputs) > maxPipelineStages {
+		return nil, fmt.Errorf("a pipeline can have at most %d stages", maxPipelineStages)
+	}
+	stages := make([]model.PipelineStage, len(inputs))
+	seen := map[string]bool{}
+	for i, input := range inputs {
+		stage := model.PipelineStage{
+			Position: i,
+			Name:     strings.TrimSpace(input.Name),
+			Category: strings.ToLower(strings.TrimSpace(input.Category)),
+		}
+		stage.ID = input.ID
+		if stage.Name == "" {
+			return nil, fmt.Errorf("stage %d: name is required", i+1)
+		}
+		if len(stage.Name) > 100 {
+			return nil, fmt.Errorf("stage %d: name must be at most 100 characters", i+1)
+		}
+		if seen[strings.ToLower(stage.Name)] {
+			return nil, fmt.Errorf("stage %d: %q is used twice", i+1, stage.Name)
+		}
+		seen[strings.ToLower(stage.Name)] = true
+		if !ContainsString(model.ApplicationStatuses, stage.Category) {
+			return nil, fmt.Errorf("stage %d: category must be one of %s", i+1, strings.Join(model.ApplicationStatuses, ", "))
+		}
+		stages[i] = stage
+	}
+	if FirstStage(stages, model.ApplicationApplied) == nil {
+		return nil, fmt.Errorf("a pipeline needs a stage in the %s category", model.ApplicationApplied)
+	}
+	return stages, nil
+}
+
+// FirstStage returns the first of the ordered stages in a category, or nil
+func FirstStage(stages []model.PipelineStage, category string) *model.PipelineStage {
+	for i := range stages {
+		if stages[i].Category == category {
+			return &stages[i]
+		}
+	}
+	return nil
+}
+
+// StageID returns the ID of a stage, or nil when there is none
+func StageID(stage *model.PipelineStage) *uint {
+	if stage == nil {
+		return nil
+	}
+	id := stage.ID
+	return &id
+}
+
+// StageName returns the name of a stage, or "" when there is none
+func StageName(stage *model.PipelineStage) string {
+	if stage == nil {
+		return ""
+	}
+	return stage.Name
+}
+
+// JobStages loads the pipeline of a job in order
+func JobStages(db *gorm.DB, jobID uint) ([]model.PipelineStage, error) {
+	var stages []model.PipelineStage
+	err := db.Where("job_id = ?", jobID).Order("position, id").Find(&stages).Error
+	return stages, err
+}
+
+// StageForStatus returns the stage an application should land in when it
+// moves to status. It stays in its current stage when that already has the
+// right category, and has no stage when the job's pipeline has none.
+func StageForStatus(db *gorm.DB, application model.Application, status string) (*model.PipelineStage, error) {
+	if application.Stage != nil && application.Stage.Category == status {
+		return application.Stage, nil
+	}
+	stages, err := JobStages(db, application.JobID)
+	if err != nil {
+		return nil, err
+	}
+	return FirstStage(stages, status), nil
+}
@@ -0,0 +1,162 @@
+package helpers
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/sahilq312/workly/model"
+)
+
+var currencySymbols = map[string]string{
+	"$": "USD",
+	"€": "EUR",
+	"£": "GBP",
+	"₹": "INR",
+	"¥": "JPY",
+}
+
+// ISO 4217 codes recognised when parsing free-form salaries
+var knownCurrencies = map[string]bool{
+	"USD": true, "EUR": true, "GBP": true, "INR": true, "JPY": true, "CAD": true, "AUD": true,
+	"CHF": true, "SGD": true, "SEK": true, "NOK": true, "DKK": true, "PLN": true, "BRL": true,
+	"MXN": true, "CNY": true, "HKD": true, "NZD": true, "ZAR": true, "AED": true,
+}
+
+var (
+	salaryAmountPattern   = regexp.MustCompile(`(?i)(\d+(?:[.,]\d+)*)\s*(k|m|l|lakh|lakhs)?\b`)
+	salaryCurrencyPattern = regexp.MustCompile(`\b([A-Z]{3})\b`)
+	hourlyPattern         = regexp.MustCompile(`(?i)\b(hour|hourly|hr|ph)\b|/\s*h\b`)
+	lpaPattern            = regexp.MustCompile(`(?i)\blpa\b`)
+	monthlyPattern        = regexp.MustCompile(`(?i)\b(month|monthly|mo|pm)\b`)
+)
+
+// ParseSalary makes a best-effort attempt to read a free-form salary such as
+// "$100k - $120k", "45,000-55,000 EUR per year" or "£30/hour". It reports false
+// when no amount could be found.
+func ParseSalary(text string) (model.Compensation, bool) {
+	var comp model.Compensation
+	if strings.TrimSpace(text) == "" {
+		return comp, false
+	}
+	lower := strings.ToLower(text)
+
+	for symbol, code := range currencySymbols {
+		if strings.Contains(text, symbol) {
+			comp.Currency = code
+			break
+		}
+	}
+	if comp.Currency == "" {
+		for _, m := range salaryCurrencyPattern.FindAllStringSubmatch(strings.ToUpper(text), -1) {
+			if knownCurrencies[m[1]] {
+				comp.Currency = m[1]
+				break
+			}
+		}
+	}
+	// "12 LPA" is lakhs per annum, the usual way to quote salaries in India
+	lpa := lpaPattern.MatchString(text)
+	if lpa && comp.Currency == "" {
+		comp.Currency = "INR"
+	}
+
+	var amounts []int64
+	for _, m := range salaryAmountPattern.FindAllStringSubmatch(text, -1) {
+		suffix := strings.ToLower(m[2])
+		if lpa && suffix == "" {
+			suffix = "l"
+		}
+		if amount, ok := parseSalaryAmount(m[1], suffix); ok {
+			amounts = append(amounts, amount)
+		}
+		if len(amounts) == 2 {
+			break
+		}
+	}
+	if len(amounts) == 0 {
+		return comp, false
+	}
+	// "50-70k" puts the suffix on the upper bound only
+	if len(amounts) == 2 && amounts[0] < 1000 && amounts[1] >= 1000 {
+		amounts[0] *= scaleOf(amounts[1])
+	}
+	min := amounts[0]
+	comp.Min = &min
+	if len(amounts) == 2 {
+		max := amounts[1]
+		if max < min {
+			min, max = max, min
+			comp.Min = &min
+		}
+		comp.Max = &max
+	}
+
+	switch {
+	case hourlyPattern.MatchString(text):
+		comp.Period = model.PayHourly
+	case monthlyPattern.MatchString(text):
+		comp.Period = model.PayMonthly
+	default:
+		comp.Period = model.PayYearly
+	}
+	comp.Equity = strings.Contains(lower, "equity") || strings.Contains(lower, "esop")
+	comp.Normalize()
+	return comp, true
+}
+
+func parseSalaryAmount(number, suffix string) (int64, bool) {
+	number = strings.ReplaceAll(number, ",", "")
+	value, err := strconv.ParseFloat(number, 64)
+	if err != nil || value <= 0 {
+		return 0, false
+	}
+	switch suffix {
+	case "k":
+		value *= 1_000
+	case "m":
+		value *= 1_000_000
+	case "l", "lakh", "lakhs":
+		value *= 100_000
+	}
+	return int64(value), true
+}
+
+// scaleOf returns the power of 1000 an amount was most likely written in
+func scaleOf(amount int64) int64 {
+	scale := int64(1)
+	for amount >= scale*1000 {
+		scale *= 1000
+	}
+	return scale
+}
+
+// ValidateCompensation checks a pay range submitted by a company and fills in
+// the derived yearly amounts
+func ValidateCompensation(comp *model.Compensation) error {
+	var errs []error
+	if comp.Min == nil && comp.Max == nil {
+		errs = append(errs, errors.New("compensation needs a min or max amount"))
+	}
+	if comp.Min != nil && *comp.Min < 0 || comp.Max != nil && *comp.Max < 0 {
+		errs = append(errs, errors.New("compensation amounts cannot be negative"))
+	}
+	if comp.Min != nil && comp.Max != nil && *comp.Min > *comp.Max {
+		errs = append(errs, errors.New("compensation min cannot exceed max"))
+	}
+	comp.Currency = strings.ToUpper(strings.TrimSpace(comp.Currency))
+	if len(comp.Currency) != 3 {
+		errs = append(errs, fmt.Errorf("compensation currency must be a 3-letter ISO 4217 code, got %q", comp.Currency))
+	}
+	switch comp.Period {
+	case model.PayHourly, model.PayMonthly, model.PayYearly:
+	case "":
+		comp.Period = model.PayYearly
+	default:
+		errs = append(errs, fmt.Errorf("compensation period must be hourly, monthly or yearly, got %q", comp.Period))
+	}
+	comp.Normalize()
+	return errors.Join(errs...)
+}
@@ -0,0 +1,296 @@
+package helpers
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/sahilq312/workly/model"
+)
+
+// Limits on screening questions per job and options per question
+const (
+	maxScreeningQuestions = 20
+	maxScreeningOptions   = 30
+	maxTextAnswerLength   = 5000
+)
+
+// QuestionInput is a screening question as sent by a company
+type QuestionInput struct {
+	Prompt   string        `json:"prompt"`
+	Type     string        `json:"type"`
+	Required bool          `json:"required"`
+	Options  []OptionInput `json:"options"`
+	// "yes" or "no" for yes/no questions
+	KnockoutAnswer string `json:"knockout_answer"`
+	// Numeric answers outside this range reject the application
+	KnockoutMin *float64 `json:"knockout_min"`
+	KnockoutMax *float64 `json:"knockout_max"`
+}
+
+// OptionInput is a choice of a choice question
+type OptionInput struct {
+	Label    string `json:"label"`
+	Knockout bool   `json:"knockout"`
+}
+
+// AnswerInput is an applicant's answer. Value is a string for text and
+// single choice questions, a boolean for yes/no, a number for numeric
+// questions and a list of option labels for multiple choice.
+type AnswerInput struct {
+	QuestionID uint            `json:"question_id"`
+	Value      json.RawMessage `json:"value"`
+}
+
+// BuildScreeningQuestions validates question inputs and converts them to
+// models in the order given
+func BuildScreeningQuestions(inputs []QuestionInput) ([]model.ScreeningQuestion, error) {
+	if len(inputs) > maxScreeningQuestions {
+		return nil, fmt.Errorf("a job can have at most %d screening questions", maxScreeningQuestions)
+	}
+	questions := make([]model.ScreeningQuestion, len(inputs))
+	for i, input := range inputs {
+		question, err := buildScreeningQuestion(input)
+		if err != nil {
+			return nil, fmt.Errorf("question %d: %w", i+1, err)
+		}
+		question.Position = i
+		questions[i] = question
+	}
+	return questions, nil
+}
+
+func buildScreeningQuestion(input QuestionInput) (model.ScreeningQuestion, error) {
+	question := model.ScreeningQuestion{
+		Prompt:   strings.TrimSpace(input.Prompt),
+		Type:     strings.ToLower(strings.TrimSpace(input.Type)),
+		Required: input.Required,
+	}
+	if question.Prompt == "" {
+		return question, errors.New("prompt is required")
+	}
+	if !ContainsString(model.QuestionTypes, question.Type) {
+		return question, errors.New("type must be one of " + strings.Join(model.QuestionTypes, ", "))
+	}
+
+	choice := question.Type == model.QuestionSingleChoice || question.Type == model.QuestionMultiChoice
+	if choice && len(input.Options) < 2 {
+		return question, errors.New("choice questions need at least two options")
+	}
+	if !choice && len(input.Options) > 0 {
+		return question, errors.New("only choice questions take options")
+	}
+	if len(input.Options) > maxScreeningOptions {
+		return question, fmt.Errorf("a question can have at most %d options", maxScreeningOptions)
+	}
+	seen := map[string]bool{}
+	knockouts := 0
+	for i, option := range input.Options {
+		label := strings.TrimSpace(option.Label)
+		if label == "" {
+			return question, errors.New("options need a label")
+		}
+		if seen[strings.ToLower(label)] {
+			return question, fmt.Errorf("option %q is listed twice", label)
+		}
+		seen[strings.ToLower(label)] = true
+		if option.Knockout {
+			knockouts++
+		}
+		question.Options = append(question.Options, model.ScreeningOption{Position: i, Label: label, Knockout: option.Knockout})
+	}
+	if question.Type == model.QuestionSingleChoice && knockouts == len(input.Options) {
+		return question, errors.New("at least one option must not be a knockout")
+	}
+
+	question.KnockoutAnswer = strings.ToLower(strings.TrimSpace(input.KnockoutAnswer))
+	if question.KnockoutAnswer != "" {
+		if question.Type != model.QuestionYesNo {
+			return question, errors.New("knockout_answer only applies to yes_no questions")
+		}
+		if question.KnockoutAnswer != "yes" && question.KnockoutAnswer != "no" {
+			return question, errors.New("knockout_answer must be yes or no")
+		}
+	}
+	if input.KnockoutMin != nil || input.KnockoutMax != nil {
+		if question.Type != model.QuestionNumber {
+			return question, errors.New("knockout_min and knockout_max only apply to number questions")
+		}
+		if input.KnockoutMin != nil && input.KnockoutMax != nil && *input.KnockoutMin > *input.KnockoutMax {
+			return question, errors.New("knockout_min cannot exceed knockout_max")
+		}
+		question.KnockoutMin, question.KnockoutMax = input.KnockoutMin, input.KnockoutMax
+	}
+	return question, nil
+}
+
+// EvaluateAnswers checks answers against a job's questions, with Options
+// loaded. It returns the answers to store, normalized, and whether any of
+// them triggered a knockout rule.
+func EvaluateAnswers(questions []model.ScreeningQuestion, inputs []AnswerInput) ([]model.ScreeningAnswer, bool, error) {
+	byID := make(map[uint]AnswerInput, len(inputs))
+	for _, input := range inputs {
+		if _, dup := byID[input.QuestionID]; dup {
+			return nil, false, fmt.Errorf("question %d is answered twice", input.QuestionID)
+		}
+		byID[input.QuestionID] = input
+	}
+
+	var answers []model.ScreeningAnswer
+	knockedOut := false
+	for _, question := range questions {
+		input, ok := byID[question.ID]
+		delete(byID, question.ID)
+		var value interface{}
+		knockout := false
+		if ok && !isJSONNull(input.Value) {
+			var err error
+			value, knockout, err = evaluateAnswer(question, input.Value)
+			if err != nil {
+				return nil, false, fmt.Errorf("question %d: %w", question.ID, err)
+			}
+		}
+		if value == nil {
+			if question.Required {
+				return nil, false, fmt.Errorf("question %d is required", question.ID)
+			}
+			continue
+		}
+		data, err := json.Marshal(value)
+		if err != nil {
+			return nil, false, err
+		}
+		answers = append(answers, model.ScreeningAnswer{
+			QuestionID: question.ID,
+			Prompt:     question.Prompt,
+			Type:       question.Type,
+			Value:      model.JSON(data),
+			Knockout:   knockout,
+		})
+		knockedOut = knockedOut || knockout
+	}
+	for id := range byID {
+		return nil, false, fmt.Errorf("question %d does not belong to this job", id)
+	}
+	return answers, knockedOut, nil
+}
+
+// evaluateAnswer parses one answer. A nil value means the question was left
+// blank.
+func evaluateAnswer(question model.ScreeningQuestion, raw json.RawMessage) (interface{}, bool, error) {
+	switch question.Type {
+	case model.QuestionText:
+		var text string
+		if err := json.Unmarshal(raw, &text); err != nil {
+			return nil, false, errors.New("answer must be text")
+		}
+		text = strings.TrimSpace(text)
+		if len(text) > maxTextAnswerLength {
+			return nil, false, fmt.Errorf("answer must be at most %d characters", maxTextAnswerLength)
+		}
+		if text == "" {
+			return nil, false, nil
+		}
+		return text, false, nil
+
+	case model.QuestionYesNo:
+		var yes bool
+		if err := json.Unmarshal(raw, &yes); err != nil {
+			var text string
+			if json.Unmarshal(raw, &text) != nil {
+				return nil, false, errors.New("answer must be yes or no")
+			}
+			switch strings.ToLower(strings.TrimSpace(text)) {
+			case "yes":
+				yes = true
+			case "no":
+			default:
+				return nil, false, errors.New("answer must be yes or no")
+			}
+		}
+		answer := "no"
+		if yes {
+			answer = "yes"
+		}
+		return yes, question.KnockoutAnswer == answer, nil
+
+	case model.QuestionSingleChoice:
+		var label string
+		if err := json.Unmarshal(raw, &label); err != nil {
+			return nil, false, errors.New("answer must be one of the options")
+		}
+		if strings.TrimSpace(label) == "" {
+			return nil, false, nil
+		}
+		option, ok := findOption(question.Options, label)
+		if !ok {
+			return nil, false, errors.New("answer must be one of the options")
+		}
+		return option.Label, option.Knockout, nil
+
+	case model.QuestionMultiChoice:
+		var labels []string
+		if err := json.Unmarshal(raw, &labels); err != nil {
+			return nil, false, errors.New("answer must be a list of options")
+		}
+		chosen := []string{}
+		seen := map[uint]bool{}
+		knockout := false
+		for _, label := range labels {
+			option, ok := findOption(question.Options, label)
+			if !ok {
+				return nil, false, fmt.Errorf("%q is not one of the options", label)
+			}
+			if !seen[option.ID] {
+				seen[option.ID] = true
+				chosen = append(chosen, option.Label)
+				knockout = knockout || option.Knockout
+			}
+		}
+		if len(chosen) == 0 {
+			return nil, false, nil
+		}
+		return chosen, knockout, nil
+
+	case model.QuestionNumber:
+		var number float64
+		if err := json.Unmarshal(raw, &number); err != nil {
+			var text string
+			if json.Unmarshal(raw, &text) != nil {
+				return nil, false, errors.New("answer must be a number")
+			}
+			if strings.TrimSpace(text) == "" {
+				return nil, false, nil
+			}
+			if number, err = strconv.ParseFloat(strings.TrimSpace(text), 64); err != nil {
+				return nil, false, errors.New("answer must be a number")
+			}
+		}
+		if math.IsNaN(number) || math.IsInf(number, 0) {
+			return nil, false, errors.New("answer must be a number")
+		}
+		knockout := (question.KnockoutMin != nil && number < *question.KnockoutMin) ||
+			(question.KnockoutMax != nil && number > *question.KnockoutMax)
+		return number, knockout, nil
+	}
+	return nil, false, fmt.Errorf("unsupported question type %q", question.Type)
+}
+
+func findOption(options []model.ScreeningOption, label string) (model.ScreeningOption, bool) {
+	label = strings.TrimSpace(label)
+	for _, option := range options {
+		if strings.EqualFold(option.Label, label) {
+			return option, true
+		}
+	}
+	return model.ScreeningOption{}, false
+}
+
+func isJSONNull(raw json.RawMessage) bool {
+	trimmed := bytes.TrimSpace(raw)
+	return len(trimmed) == 0 || bytes.Equal(trimmed, []byte("null"))
+}
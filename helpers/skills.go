@@ -0,0 +1,134 @@
+package helpers
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/sahilq312/workly/model"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Many-to-many tables referencing skills, with the column holding the owner
+var skillJoinTables = []struct{ table, owner string }{
+	{"job_skills", "job_id"},
+	{"user_skills", "user_id"},
+	{"experience_skills", "experience_id"},
+}
+
+// ResolveSkills maps free-text skill names to canonical skills, matching by
+// normalized name or alias and creating skills that are not known yet.
+// Duplicates are dropped, so "Golang" and "go" resolve to a single Go.
+func ResolveSkills(db *gorm.DB, names []string) ([]model.Skill, error) {
+	var skills []model.Skill
+	seen := map[uint]bool{}
+	for _, name := range names {
+		skill, err := ResolveSkill(db, name)
+		if err != nil {
+			return nil, err
+		}
+		if skill.ID == 0 || seen[skill.ID] {
+			continue
+		}
+		seen[skill.ID] = true
+		skills = append(skills, skill)
+	}
+	return skills, nil
+}
+
+// ResolveSkill returns the canonical skill for a name, creating it if needed.
+// Blank names resolve to the zero Skill.
+func ResolveSkill(db *gorm.DB, name string) (model.Skill, error) {
+	key := model.SkillKey(name)
+	if key == "" {
+		return model.Skill{}, nil
+	}
+	skill, found, err := FindSkill(db, key)
+	if err != nil || found {
+		return skill, err
+	}
+
+	skill = model.Skill{Name: model.CleanSkillName(name), Key: key}
+	if err := db.Clauses(clause.OnConflict{DoNothing: true}).Create(&skill).Error; err != nil {
+		return model.Skill{}, fmt.Errorf("skill %q: %w", name, err)
+	}
+	if skill.ID == 0 {
+		// Created concurrently, or the name is held by a deleted skill
+		skill, found, err = FindSkill(db, key)
+		if err == nil && !found {
+			err = fmt.Errorf("skill %q conflicts with a deleted skill", name)
+		}
+	}
+	return skill, err
+}
+
+// FindSkill looks up a skill by normalized name or alias
+func FindSkill(db *gorm.DB, key string) (model.Skill, bool, error) {
+	var skill model.Skill
+	err := db.Where("key = ?", key).
+		Or("id IN (SELECT skill_id FROM skill_aliases WHERE key = ? AND deleted_at IS NULL)", key).
+		// A skill's own name wins over another skill's alias
+		Order(clause.OrderBy{Expression: clause.Expr{SQL: "key = ? DESC", Vars: []interface{}{key}}}).
+		First(&skill).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return model.Skill{}, false, nil
+	}
+	return skill, err == nil, err
+}
+
+// MergeSkills folds the source skills into target: every job, user and
+// experience using a source skill uses target instead, and the source names
+// and aliases become aliases of target. Run it inside a transaction.
+func MergeSkills(tx *gorm.DB, target model.Skill, sources []model.Skill) error {
+	var ids []uint
+	for _, source := range sources {
+		if source.ID != target.ID {
+			ids = append(ids, source.ID)
+		}
+	}
+	if len(ids) == 0 {
+		return nil
+	}
+
+	for _, join := range skillJoinTables {
+		err := tx.Exec(fmt.Sprintf(
+			"INSERT INTO %[1]s (%[2]s, skill_id) SELECT DISTINCT %[2]s, ? FROM %[1]s WHERE skill_id IN ? ON CONFLICT DO NOTHING",
+			join.table, join.owner), target.ID, ids).Error
+		if err != nil {
+			return fmt.Errorf("%s: %w", join.table, err)
+		}
+		if err := tx.Exec(fmt.Sprintf("DELETE FROM %s WHERE skill_id IN ?", join.table), ids).Error; err != nil {
+			return fmt.Errorf("%s: %w", join.table, err)
+		}
+	}
+
+	if err := tx.Model(&model.SkillAlias{}).Where("skill_id IN ?", ids).Update("skill_id", target.ID).Error; err != nil {
+		return err
+	}
+	if err := tx.Unscoped().Delete(&model.Skill{}, ids).Error; err != nil {
+		return err
+	}
+	for _, source := range sources {
+		if source.ID == target.ID {
+			continue
+		}
+		if err := AddSkillAlias(tx, target, source.Name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// AddSkillAlias records name as another name for skill. Names equal to the
+// skill's own are ignored; an alias already pointing elsewhere is moved.
+func AddSkillAlias(db *gorm.DB, skill model.Skill, name string) error {
+	key := model.SkillKey(name)
+	if key == "" || key == skill.Key {
+		return nil
+	}
+	alias := model.SkillAlias{SkillID: skill.ID, Name: model.CleanSkillName(name), Key: key}
+	return db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "key"}},
+		DoUpdates: clause.AssignmentColumns([]string{"skill_id", "name", "updated_at"}),
+	}).Create(&alias).Error
+}
@@ -0,0 +1,164 @@
+// Package ical writes iCalendar (RFC 5545) data: single-event invites sent
+// by email and calendar feeds that clients subscribe to.
+package ical
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// ContentType is the media type calendars are served with
+const ContentType = "text/calendar; charset=utf-8"
+
+// iTIP methods (RFC 5546) for invites. Feeds leave Method empty.
+const (
+	MethodRequest = "REQUEST"
+	MethodCancel  = "CANCEL"
+)
+
+// Event states
+const (
+	StatusTentative = "TENTATIVE"
+	StatusConfirmed = "CONFIRMED"
+	StatusCancelled = "CANCELLED"
+)
+
+// Calendar is a VCALENDAR. Name and RefreshInterval are hints for clients
+// subscribed to a feed.
+type Calendar struct {
+	ProdID          string
+	Name            string
+	Method          string
+	RefreshInterval time.Duration
+	Events          []Event
+}
+
+// Person is an organizer or attendee
+type Person struct {
+	Name  string
+	Email string
+}
+
+// Event is a VEVENT. Sequence must grow every time an event that was already
+// sent changes, so clients replace their copy instead of ignoring the update.
+// Times are written in UTC.
+type Event struct {
+	UID         string
+	Sequence    int
+	Stamp       time.Time
+	Start       time.Time
+	End         time.Time
+	Summary     string
+	Description string
+	Location    string
+	URL         string
+	Status      string
+	Organizer   *Person
+	Attendees   []Person
+}
+
+// Marshal renders the calendar with CRLF line endings and folded lines
+func (c Calendar) Marshal() []byte {
+	var w writer
+	w.line("BEGIN:VCALENDAR")
+	w.line("VERSION:2.0")
+	w.line("PRODID:" + c.ProdID)
+	w.line("CALSCALE:GREGORIAN")
+	if c.Method != "" {
+		w.line("METHOD:" + c.Method)
+	}
+	if c.Name != "" {
+		w.line("X-WR-CALNAME:" + escapeText(c.Name))
+	}
+	if c.RefreshInterval > 0 {
+		minutes := int(c.RefreshInterval / time.Minute)
+		w.line(fmt.Sprintf("REFRESH-INTERVAL;VALUE=DURATION:PT%dM", minutes))
+		w.line(fmt.Sprintf("X-PUBLISHED-TTL:PT%dM", minutes))
+	}
+	for _, event := range c.Events {
+		event.write(&w)
+	}
+	w.line("END:VCALENDAR")
+	return w.buf.Bytes()
+}
+
+func (e Event) write(w *writer) {
+	w.line("BEGIN:VEVENT")
+	w.line("UID:" + e.UID)
+	w.line("DTSTAMP:" + formatTime(e.Stamp))
+	w.line(fmt.Sprintf("SEQUENCE:%d", e.Sequence))
+	w.line("DTSTART:" + formatTime(e.Start))
+	w.line("DTEND:" + formatTime(e.End))
+	w.line("SUMMARY:" + escapeText(e.Summary))
+	if e.Description != "" {
+		w.line("DESCRIPTION:" + escapeText(e.Description))
+	}
+	if e.Location != "" {
+		w.line("LOCATION:" + escapeText(e.Location))
+	}
+	if e.URL != "" {
+		w.line("URL:" + e.URL)
+	}
+	if e.Status != "" {
+		w.line("STATUS:" + e.Status)
+	}
+	if e.Organizer != nil {
+		w.line("ORGANIZER" + commonName(e.Organizer.Name) + ":mailto:" + e.Organizer.Email)
+	}
+	for _, attendee := range e.Attendees {
+		w.line("ATTENDEE" + commonName(attendee.Name) + ";ROLE=REQ-PARTICIPANT;PARTSTAT=NEEDS-ACTION;RSVP=TRUE:mailto:" + attendee.Email)
+	}
+	w.line("END:VEVENT")
+}
+
+// formatTime writes a UTC date-time (RFC 5545 section 3.3.5, form #2)
+func formatTime(t time.Time) string {
+	return t.UTC().Format("20060102T150405Z")
+}
+
+// escapeText escapes a TEXT value (RFC 5545 section 3.3.11)
+func escapeText(s string) string {
+	return strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`, "\r", "").Replace(s)
+}
+
+// commonName renders a CN parameter. Quoted values may not contain quotes.
+func commonName(name string) string {
+	name = strings.Map(func(r rune) rune {
+		if r == '"' || r < ' ' || r == 0x7f {
+			return -1
+		}
+		return r
+	}, name)
+	if name == "" {
+		return ""
+	}
+	return `;CN="` + name + `"`
+}
+
+// Lines longer than this many octets are folded (RFC 5545 section 3.1)
+const maxLineOctets = 75
+
+type writer struct {
+	buf bytes.Buffer
+}
+
+// line writes a content line, folding it without splitting UTF-8 sequences
+func (w *writer) line(s string) {
+	limit := maxLineOctets
+	for len(s) > limit {
+		cut := limit
+		for cut > 0 && !utf8.RuneStart(s[cut]) {
+			cut--
+		}
+		w.buf.WriteString(s[:cut])
+		w.buf.WriteString("\r\n ")
+		s = s[cut:]
+		// The leading space of a continuation line counts towards its length
+		limit = maxLineOctets - 1
+	}
+	w.buf.WriteString(s)
+	w.buf.WriteString("\r\n")
+}
@@ -0,0 +1,44 @@
+package initializer
+
+import (
+	"errors"
+	"io/fs"
+	"log"
+
+	"github.com/joho/godotenv"
+	"github.com/sahilq312/workly/config"
+	"github.com/sahilq312/workly/geo"
+)
+
+var Config *config.Config
+
+// LoadConfig reads an optional .env file and builds the typed configuration
+// of the API server. A missing .env is fine; real environment variables take
+// precedence over it.
+func LoadConfig() {
+	loadConfig(config.ScopeServer)
+}
+
+// LoadDatabaseConfig builds the configuration for tools that only use the
+// database, without requiring server settings such as the JWT secrets
+func LoadDatabaseConfig() {
+	loadConfig(config.ScopeDatabase)
+}
+
+func loadConfig(scope config.Scope) {
+	if err := godotenv.Load(); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		log.Fatalf("Error in loading the env file: %v", err)
+	}
+
+	cfg, err := config.Load("", scope)
+	if err != nil {
+		log.Fatal(err)
+	}
+	Config = cfg
+
+	if cfg.Geo.GazetteerFile != "" {
+		if err := geo.Load(cfg.Geo.GazetteerFile); err != nil {
+			log.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,10 @@
+package initializer
+
+import "github.com/sahilq312/workly/mailer"
+
+var Mailer mailer.Mailer
+
+// SetupMailer configures outgoing email from Config
+func SetupMailer() {
+	Mailer = mailer.New(Config.Mail)
+}
@@ -1,21 +1,130 @@
 package initializer
 
 import (
+	"context"
+	"fmt"
 	"log"
-	"os"
+	"net/url"
+	"strconv"
+	"sync/atomic"
+	"time"
 
+	"github.com/sahilq312/workly/config"
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
 )
 
+// DB is the primary database and receives every write
 var DB *gorm.DB
 
+// replicas serve read-only queries routed through Reader
+var replicas []*gorm.DB
+var nextReplica uint64
+
+// Longest wait between two connection attempts
+const maxConnectBackoff = 30 * time.Second
+
 func ConnectPostgresDatabase() {
 	var err error
-	dsn := os.Getenv("POSTGRES_URL")
-	DB, err = gorm.Open(postgres.Open(dsn), &gorm.Config{})
+	DB, err = openWithRetry("primary", Config.Database.URL, Config.Database)
+	if err != nil {
+		log.Fatalf("Cannot connect to the postgres database: %v", err)
+	}
+
+	for i, url := range Config.Database.ReplicaURLs {
+		replica, err := openWithRetry(fmt.Sprintf("replica %d", i+1), url, Config.Database)
+		if err != nil {
+			// A missing replica degrades to reading from the primary
+			log.Printf("Skipping read replica %d: %v", i+1, err)
+			continue
+		}
+		replicas = append(replicas, replica)
+	}
+}
 
+// Writer returns the primary database bound to ctx
+func Writer(ctx context.Context) *gorm.DB {
+	return DB.WithContext(ctx)
+}
+
+// Reader returns a read replica bound to ctx, chosen round-robin, or the
+// primary when no replica is configured. Only use it for queries that can
+// tolerate replication lag.
+func Reader(ctx context.Context) *gorm.DB {
+	if len(replicas) == 0 {
+		return DB.WithContext(ctx)
+	}
+	n := atomic.AddUint64(&nextReplica, 1)
+	return replicas[n%uint64(len(replicas))].WithContext(ctx)
+}
+
+// Databases returns the primary followed by every connected replica
+func Databases() []*gorm.DB {
+	return append([]*gorm.DB{DB}, replicas...)
+}
+
+// openWithRetry opens a pooled connection and pings it, backing off
+// exponentially between failed attempts
+func openWithRetry(name, dsn string, cfg config.DatabaseConfig) (*gorm.DB, error) {
+	backoff := cfg.ConnectBackoff
+	var lastErr error
+	for attempt := 1; attempt <= cfg.ConnectRetries; attempt++ {
+		db, err := open(dsn, cfg)
+		if err == nil {
+			return db, nil
+		}
+		lastErr = err
+		if attempt == cfg.ConnectRetries {
+			break
+		}
+		log.Printf("Connecting to %s database failed (attempt %d/%d), retrying in %s: %v", name, attempt, cfg.ConnectRetries, backoff, err)
+		time.Sleep(backoff)
+		backoff *= 2
+		if backoff > maxConnectBackoff {
+			backoff = maxConnectBackoff
+		}
+	}
+	return nil, lastErr
+}
+
+func open(dsn string, cfg config.DatabaseConfig) (*gorm.DB, error) {
+	db, err := gorm.Open(postgres.Open(withStatementTimeout(dsn, cfg.StatementTimeout)), &gorm.Config{
+		// Report constraint violations as gorm.ErrDuplicatedKey and friends
+		TranslateError: true,
+	})
+	if err != nil {
+		return nil, err
+	}
+	sqlDB, err := db.DB()
 	if err != nil {
-		log.Fatal("Cannot connect to the postgres database")
+		return nil, err
+	}
+	sqlDB.SetMaxOpenConns(cfg.MaxOpenConns)
+	sqlDB.SetMaxIdleConns(cfg.MaxIdleConns)
+	sqlDB.SetConnMaxLifetime(cfg.ConnMaxLifetime)
+	sqlDB.SetConnMaxIdleTime(cfg.ConnMaxIdleTime)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := sqlDB.PingContext(ctx); err != nil {
+		sqlDB.Close()
+		return nil, err
+	}
+	return db, nil
+}
+
+// withStatementTimeout makes Postgres cancel any statement running longer
+// than timeout, so queries that do not use a request context are bounded too
+func withStatementTimeout(dsn string, timeout time.Duration) string {
+	if timeout <= 0 {
+		return dsn
+	}
+	ms := strconv.FormatInt(timeout.Milliseconds(), 10)
+	if u, err := url.Parse(dsn); err == nil && (u.Scheme == "postgres" || u.Scheme == "postgresql") {
+		query := u.Query()
+		query.Set("statement_timeout", ms)
+		u.RawQuery = query.Encode()
+		return u.String()
 	}
+	return dsn + " statement_timeout=" + ms
 }
@@ -0,0 +1,18 @@
+package initializer
+
+import (
+	"log"
+
+	"github.com/sahilq312/workly/storage"
+)
+
+var Storage storage.Storage
+
+// SetupStorage opens the file storage backend selected in Config
+func SetupStorage() {
+	s, err := storage.New(Config.Storage)
+	if err != nil {
+		log.Fatal(err)
+	}
+	Storage = s
+}
@@ -0,0 +1,141 @@
+package mailer
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"log"
+	"mime"
+	"mime/multipart"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"net/textproto"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/sahilq312/workly/config"
+)
+
+// Message is a plain-text email, optionally with attachments
+type Message struct {
+	To      string
+	Subject string
+	Body    string
+	// Extra headers such as List-Unsubscribe
+	Headers     map[string]string
+	Attachments []Attachment
+}
+
+// Attachment is a file sent along with a message
+type Attachment struct {
+	FileName    string
+	ContentType string
+	Data        []byte
+}
+
+// Mailer delivers email
+type Mailer interface {
+	Send(msg Message) error
+}
+
+// New returns an SMTP mailer, or one that only logs messages when no SMTP
+// host is configured (development)
+func New(cfg config.MailConfig) Mailer {
+	if cfg.SMTPHost == "" {
+		return logMailer{}
+	}
+	return smtpMailer{cfg: cfg}
+}
+
+type logMailer struct{}
+
+func (logMailer) Send(msg Message) error {
+	log.Printf("mail (not sent, SMTP not configured) to=%s subject=%q attachments=%d\n%s", msg.To, msg.Subject, len(msg.Attachments), msg.Body)
+	return nil
+}
+
+type smtpMailer struct {
+	cfg config.MailConfig
+}
+
+func (m smtpMailer) Send(msg Message) error {
+	addr := net.JoinHostPort(m.cfg.SMTPHost, strconv.Itoa(m.cfg.SMTPPort))
+	var auth smtp.Auth
+	if m.cfg.Username != "" {
+		auth = smtp.PlainAuth("", m.cfg.Username, m.cfg.Password, m.cfg.SMTPHost)
+	}
+	from, err := mail.ParseAddress(m.cfg.From)
+	if err != nil {
+		return fmt.Errorf("mailer: invalid from address: %w", err)
+	}
+	if err := smtp.SendMail(addr, auth, from.Address, []string{msg.To}, build(m.cfg.From, msg)); err != nil {
+		return fmt.Errorf("mailer: sending to %s: %w", msg.To, err)
+	}
+	return nil
+}
+
+// build renders the message with RFC 5322 headers
+func build(from string, msg Message) []byte {
+	headers := map[string]string{
+		"From":                      from,
+		"To":                        msg.To,
+		"Subject":                   mime.QEncoding.Encode("utf-8", msg.Subject),
+		"Date":                      time.Now().Format(time.RFC1123Z),
+		"MIME-Version":              "1.0",
+		"Content-Type":              "text/plain; charset=utf-8",
+		"Content-Transfer-Encoding": "8bit",
+	}
+	for key, value := range msg.Headers {
+		headers[key] = value
+	}
+
+	body := []byte(strings.ReplaceAll(msg.Body, "\n", "\r\n"))
+	if len(msg.Attachments) > 0 {
+		var parts bytes.Buffer
+		writer := multipart.NewWriter(&parts)
+		headers["Content-Type"] = "multipart/mixed; boundary=" + writer.Boundary()
+		delete(headers, "Content-Transfer-Encoding")
+		writeParts(writer, msg)
+		body = parts.Bytes()
+	}
+
+	keys := make([]string, 0, len(headers))
+	for key := range headers {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var buf bytes.Buffer
+	for _, key := range keys {
+		fmt.Fprintf(&buf, "%s: %s\r\n", key, headers[key])
+	}
+	buf.WriteString("\r\n")
+	buf.Write(body)
+	return buf.Bytes()
+}
+
+// writeParts writes the text of msg followed by its attachments, base64 encoded
+func writeParts(parts *multipart.Writer, msg Message) {
+	text, _ := parts.CreatePart(textproto.MIMEHeader{
+		"Content-Type":              {"text/plain; charset=utf-8"},
+		"Content-Transfer-Encoding": {"8bit"},
+	})
+	text.Write([]byte(strings.ReplaceAll(msg.Body, "\n", "\r\n")))
+	for _, attachment := range msg.Attachments {
+		part, _ := parts.CreatePart(textproto.MIMEHeader{
+			"Content-Type":              {attachment.ContentType},
+			"Content-Disposition":       {mime.FormatMediaType("attachment", map[string]string{"filename": attachment.FileName})},
+			"Content-Transfer-Encoding": {"base64"},
+		})
+		encoded := base64.StdEncoding.EncodeToString(attachment.Data)
+		for len(encoded) > 76 {
+			part.Write([]byte(encoded[:76] + "\r\n"))
+			encoded = encoded[76:]
+		}
+		part.Write([]byte(encoded + "\r\n"))
+	}
+	parts.Close()
+}
@@ -4,21 +4,27 @@ import (
 	"context"
 	"log"
 	"net/http"
-	"os"
 	"os/signal"
 	"syscall"
 	"time"
+	// Interview time zones must resolve on images without zoneinfo
+	_ "time/tzdata"
 
 	"github.com/gin-contrib/cors"
 	"github.com/gin-gonic/gin"
+	"github.com/sahilq312/workly/health"
 	"github.com/sahilq312/workly/initializer"
 	"github.com/sahilq312/workly/middleware"
 	"github.com/sahilq312/workly/routes"
+	"github.com/sahilq312/workly/seo"
+	"github.com/sahilq312/workly/worker"
 )
 
 func init() {
-	initializer.LoadEnvVariale()
+	initializer.LoadConfig()
 	initializer.ConnectPostgresDatabase()
+	initializer.SetupMailer()
+	initializer.SetupStorage()
 }
 
 func main() {
@@ -26,7 +32,7 @@ func main() {
 
 	// Set up custom CORS configuration
 	corsConfig := cors.Config{
-		AllowOrigins:     []string{"http://localhost:3000"}, // Allow your frontend origin here
+		AllowOrigins:     initializer.Config.Server.CORSOrigins,
 		AllowMethods:     []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"},
 		AllowHeaders:     []string{"Origin", "Content-Type", "Authorization"},
 		ExposeHeaders:    []string{"Content-Length"},
@@ -34,12 +40,14 @@ func main() {
 		MaxAge:           12 * time.Hour,
 	}
 	r.Use(cors.New(corsConfig))
+	r.Use(middleware.StatementTimeout)
+	r.SetHTMLTemplate(seo.Templates)
 
 	// Set up routes and start the server
 	setupRoutes(r)
 
 	srv := &http.Server{
-		Addr:    ":" + getPort(),
+		Addr:    ":" + initializer.Config.Server.Port,
 		Handler: r,
 	}
 
@@ -47,8 +55,12 @@ func main() {
 	defer stop()
 
 	go startServer(srv)
+	worker.StartJobExpirer(ctx, initializer.Config.Workers.JobExpiryInterval)
+	worker.StartJobAlerts(ctx, initializer.Config.Workers.JobAlertInterval)
 
 	<-ctx.Done()
+	// Restore default signal handling so a second Ctrl+C forces exit
+	stop()
 	gracefulShutdown(srv)
 }
 
@@ -57,6 +69,7 @@ func setupRoutes(r *gin.Engine) {
 	r.GET("/", welcomeHandler)
 	r.GET("/health", middleware.RequireAuth, healthCheckHandler)
 	r.GET("/company-health", middleware.CompanyAuth, healthCompanyCheckHandler)
+	routes.HealthRoutes(r)
 	routes.AuthRoutes(r)
 	routes.PostRoutes(r)
 	routes.CompanyRoutes(r)
@@ -65,6 +78,16 @@ func setupRoutes(r *gin.Engine) {
 	routes.LikeRoutes(r)
 	routes.CommentRoutes(r)
 	routes.ApplicationRoutes(r)
+	routes.DocumentRoutes(r)
+	routes.PipelineRoutes(r)
+	routes.InterviewRoutes(r)
+	routes.JobCategoryRoutes(r)
+	routes.SkillRoutes(r)
+	routes.SavedSearchRoutes(r)
+	routes.NotificationRoutes(r)
+	routes.FeedRoutes(r)
+	routes.PageRoutes(r)
+	routes.AuditRoutes(r)
 }
 
 func welcomeHandler(c *gin.Context) {
@@ -81,14 +104,6 @@ func healthCompanyCheckHandler(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{"message": "Company auth is Working Fine", "company": company})
 }
 
-func getPort() string {
-	port := os.Getenv("PORT")
-	if port == "" {
-		port = "8080"
-	}
-	return port
-}
-
 func startServer(srv *http.Server) {
 	if err := srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
 		log.Fatalf("listen: %s\n", err)
@@ -97,7 +112,12 @@ func startServer(srv *http.Server) {
 
 func gracefulShutdown(srv *http.Server) {
 	log.Println("Shutting down gracefully, press Ctrl+C again to force")
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+
+	// Fail readiness first so load balancers stop routing new traffic here
+	health.SetShuttingDown()
+	time.Sleep(initializer.Config.Server.DrainDelay)
+
+	ctx, cancel := context.WithTimeout(context.Background(), initializer.Config.Server.ShutdownTimeout)
 	defer cancel()
 	if err := srv.Shutdown(ctx); err != nil {
 		log.Fatal("Server forced to shutdown: ", err)
@@ -0,0 +1,173 @@
+package matching
+
+import (
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/sahilq312/workly/model"
+)
+
+// Share of each component in a candidate's score
+const (
+	candidateSkillsShare     = 0.6
+	candidateExperienceShare = 0.3
+	candidateEducationShare  = 0.1
+)
+
+// Years of experience after which more no longer raises the score
+const targetYears = 5.0
+
+// CandidateBreakdown holds each candidate score component in [0, 1]
+type CandidateBreakdown struct {
+	Skills     float64 `json:"skills"`
+	Experience float64 `json:"experience"`
+	Education  float64 `json:"education"`
+}
+
+// CandidateMatch explains how well an applicant fits a job. Score is out of 100.
+type CandidateMatch struct {
+	Score         float64  `json:"score"`
+	MatchedSkills []string `json:"matched_skills"`
+	MissingSkills []string `json:"missing_skills"`
+	// Skills the applicant has that the job does not ask for
+	ExtraSkills []string `json:"extra_skills"`
+	// Years across all roles, and across roles using one of the job's skills
+	YearsExperience  float64            `json:"years_experience"`
+	RelevantYears    float64            `json:"relevant_years"`
+	HighestEducation string             `json:"highest_education"`
+	Breakdown        CandidateBreakdown `json:"breakdown"`
+}
+
+// ScoreCandidate rates an applicant with Skills, Experience.Skills and
+// Education loaded against a job with Skills loaded
+func ScoreCandidate(user model.User, job model.Job, now time.Time) CandidateMatch {
+	m := CandidateMatch{MatchedSkills: []string{}, MissingSkills: []string{}, ExtraSkills: []string{}}
+
+	// Every skill the applicant lists or used in a role, by key
+	has := map[string]string{}
+	for _, skill := range user.Skills {
+		has[model.SkillKey(skill.Name)] = skill.Name
+	}
+	for _, experience := range user.Experience {
+		for _, skill := range experience.Skills {
+			if _, ok := has[model.SkillKey(skill.Name)]; !ok {
+				has[model.SkillKey(skill.Name)] = skill.Name
+			}
+		}
+	}
+
+	wanted := map[string]bool{}
+	for _, skill := range job.Skills {
+		key := model.SkillKey(skill.Name)
+		wanted[key] = true
+		if _, ok := has[key]; ok {
+			m.MatchedSkills = append(m.MatchedSkills, skill.Name)
+		} else {
+			m.MissingSkills = append(m.MissingSkills, skill.Name)
+		}
+	}
+	for key, name := range has {
+		if !wanted[key] {
+			m.ExtraSkills = append(m.ExtraSkills, name)
+		}
+	}
+	sort.Strings(m.MatchedSkills)
+	sort.Strings(m.MissingSkills)
+	sort.Strings(m.ExtraSkills)
+	if len(job.Skills) > 0 {
+		m.Breakdown.Skills = float64(len(m.MatchedSkills)) / float64(len(job.Skills))
+	}
+
+	var all, relevant []span
+	for _, experience := range user.Experience {
+		s := span{experience.StartDate, experienceEnd(experience, now)}
+		if s.start.IsZero() || !s.end.After(s.start) {
+			continue
+		}
+		all = append(all, s)
+		for _, skill := range experience.Skills {
+			if wanted[model.SkillKey(skill.Name)] {
+				relevant = append(relevant, s)
+				break
+			}
+		}
+	}
+	m.YearsExperience = roundYears(coveredYears(all))
+	m.RelevantYears = roundYears(coveredYears(relevant))
+	m.Breakdown.Experience = 0.7*math.Min(m.RelevantYears/targetYears, 1) + 0.3*math.Min(m.YearsExperience/targetYears, 1)
+
+	for _, education := range user.Education {
+		if level := degreeLevel(education.Degree); level > m.Breakdown.Education {
+			m.Breakdown.Education = level
+			m.HighestEducation = strings.TrimSpace(education.Degree + " " + education.Field)
+		}
+	}
+
+	score := candidateSkillsShare*m.Breakdown.Skills +
+		candidateExperienceShare*m.Breakdown.Experience +
+		candidateEducationShare*m.Breakdown.Education
+	m.Score = math.Round(score*1000) / 10
+	return m
+}
+
+type span struct {
+	start, end time.Time
+}
+
+// coveredYears is the time covered by spans, counting overlapping roles once
+func coveredYears(spans []span) float64 {
+	sort.Slice(spans, func(i, j int) bool { return spans[i].start.Before(spans[j].start) })
+	var total time.Duration
+	var current span
+	for i, s := range spans {
+		switch {
+		case i == 0:
+			current = s
+		case s.start.After(current.end):
+			total += current.end.Sub(current.start)
+			current = s
+		case s.end.After(current.end):
+			current.end = s.end
+		}
+	}
+	if len(spans) > 0 {
+		total += current.end.Sub(current.start)
+	}
+	return total.Hours() / (24 * 365.25)
+}
+
+func roundYears(years float64) float64 {
+	return math.Round(years*10) / 10
+}
+
+// degreeLevel rates a free-text degree such as "B.Tech", "Master of Science"
+// or "PhD" from 0 (unknown) to 1 (doctorate)
+func degreeLevel(degree string) float64 {
+	d := strings.ToLower(strings.ReplaceAll(degree, ".", ""))
+	words := strings.Fields(d)
+	if len(words) == 0 {
+		return 0
+	}
+	switch {
+	case strings.Contains(d, "doctor") || words[0] == "phd" || words[0] == "dphil":
+		return 1
+	case strings.Contains(d, "master") || oneOf(words[0], "msc", "ms", "ma", "mtech", "meng", "mba", "mphil"):
+		return 0.8
+	case strings.Contains(d, "bachelor") || oneOf(words[0], "bsc", "bs", "ba", "btech", "beng", "be", "bca", "bba"):
+		return 0.6
+	case strings.Contains(d, "associate") || strings.Contains(d, "diploma"):
+		return 0.4
+	}
+	return 0.2
+}
+
+func oneOf(s string, options ...string) bool {
+	for _, option := range options {
+		if s == option {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,131 @@
+// Package matching scores how well candidates and jobs fit each other
+package matching
+
+import (
+	"strings"
+	"time"
+
+	"github.com/sahilq312/workly/geo"
+	"github.com/sahilq312/workly/model"
+)
+
+// Weight of skills listed on the profile itself
+const declaredSkillWeight = 1.0
+
+// Experience skills count fully for current or recent roles and fade to
+// staleSkillWeight for roles that ended staleAfter ago or earlier
+const (
+	staleSkillWeight = 0.5
+	freshFor         = 365 * 24 * time.Hour
+	staleAfter       = 5 * 365 * 24 * time.Hour
+)
+
+// Profile is what is known about a candidate when matching them against jobs
+type Profile struct {
+	// Skill weights in [0, 1], keyed by model.SkillKey
+	Skills map[string]float64
+	// Skills of jobs the candidate applied to, a signal of interest
+	Interests map[string]bool
+	// Places the candidate wants to work in or near
+	Places []geo.Place
+	// Jobs the candidate already applied to
+	Applied map[uint]bool
+}
+
+// NewProfile builds a profile from a user with Skills and Experience.Skills
+// loaded and their applications with Job.Skills and Job.Locations loaded.
+// Location preference comes from the most recent experience and the places of
+// jobs applied to.
+func NewProfile(user model.User, applications []model.Application, now time.Time) Profile {
+	p := Profile{
+		Skills:    map[string]float64{},
+		Interests: map[string]bool{},
+		Applied:   map[uint]bool{},
+	}
+	for _, skill := range user.Skills {
+		p.addSkill(skill.Name, declaredSkillWeight)
+	}
+
+	var latest *model.Experience
+	for i, experience := range user.Experience {
+		weight := experienceWeight(experience, now)
+		for _, skill := range experience.Skills {
+			p.addSkill(skill.Name, weight)
+		}
+		if latest == nil || experienceEnd(experience, now).After(experienceEnd(*latest, now)) {
+			latest = &user.Experience[i]
+		}
+	}
+	if latest != nil {
+		p.AddPlace(latest.Location)
+	}
+
+	for _, application := range applications {
+		p.Applied[application.JobID] = true
+		for _, skill := range application.Job.Skills {
+			p.Interests[model.SkillKey(skill.Name)] = true
+		}
+		for _, location := range application.Job.Locations {
+			if location.Latitude != nil && location.Longitude != nil {
+				p.addPlace(geo.Place{City: location.City, Region: location.Region, Country: location.Country, Latitude: *location.Latitude, Longitude: *location.Longitude})
+			}
+		}
+	}
+	return p
+}
+
+// AddPlace adds a preferred place by name, ignoring names the gazetteer does not know
+func (p *Profile) AddPlace(name string) bool {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return false
+	}
+	place, ok := geo.Lookup(name)
+	if !ok {
+		// "Berlin, Germany" is not qualified by an ISO code; try the city alone
+		place, ok = geo.Lookup(strings.Split(name, ",")[0])
+	}
+	if ok {
+		p.addPlace(place)
+	}
+	return ok
+}
+
+func (p *Profile) addPlace(place geo.Place) {
+	for _, known := range p.Places {
+		if known.City == place.City && known.Country == place.Country {
+			return
+		}
+	}
+	p.Places = append(p.Places, place)
+}
+
+func (p *Profile) addSkill(name string, weight float64) {
+	key := model.SkillKey(name)
+	if key == "" {
+		return
+	}
+	if weight > p.Skills[key] {
+		p.Skills[key] = weight
+	}
+}
+
+// experienceEnd is when a role ended, or now for a current role
+func experienceEnd(experience model.Experience, now time.Time) time.Time {
+	if experience.EndDate.IsZero() || experience.EndDate.After(now) {
+		return now
+	}
+	return experience.EndDate
+}
+
+func experienceWeight(experience model.Experience, now time.Time) float64 {
+	age := now.Sub(experienceEnd(experience, now))
+	switch {
+	case age <= freshFor:
+		return 1
+	case age >= staleAfter:
+		return staleSkillWeight
+	}
+	faded := float64(age-freshFor) / float64(staleAfter-freshFor)
+	return 1 - faded*(1-staleSkillWeight)
+}
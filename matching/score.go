@@ -0,0 +1,129 @@
+package matching
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/sahilq312/workly/geo"
+	"github.com/sahilq312/workly/model"
+)
+
+// Share of each component in the final score
+const (
+	skillsShare    = 0.6
+	locationShare  = 0.2
+	interestShare  = 0.1
+	freshnessShare = 0.1
+)
+
+// Distances within which a job location counts as near a preferred place
+const (
+	nearKm   = 50
+	regionKm = 150
+)
+
+// Postings lose all freshness credit after this long
+const freshnessWindow = 30 * 24 * time.Hour
+
+// Breakdown holds each score component in [0, 1]
+type Breakdown struct {
+	Skills    float64 `json:"skills"`
+	Location  float64 `json:"location"`
+	Interest  float64 `json:"interest"`
+	Freshness float64 `json:"freshness"`
+}
+
+// Match explains how well a profile fits a job. Score is out of 100.
+type Match struct {
+	Score         float64   `json:"score"`
+	MatchedSkills []string  `json:"matched_skills"`
+	MissingSkills []string  `json:"missing_skills"`
+	Location      string    `json:"location"`
+	Breakdown     Breakdown `json:"breakdown"`
+}
+
+// Score rates a job with Skills and Locations loaded against a profile
+func Score(p Profile, job model.Job, now time.Time) Match {
+	m := Match{MatchedSkills: []string{}, MissingSkills: []string{}}
+
+	var skillTotal float64
+	interests := 0
+	for _, skill := range job.Skills {
+		key := model.SkillKey(skill.Name)
+		if weight, ok := p.Skills[key]; ok {
+			skillTotal += weight
+			m.MatchedSkills = append(m.MatchedSkills, skill.Name)
+		} else {
+			m.MissingSkills = append(m.MissingSkills, skill.Name)
+		}
+		if p.Interests[key] {
+			interests++
+		}
+	}
+	sort.Strings(m.MatchedSkills)
+	sort.Strings(m.MissingSkills)
+	if len(job.Skills) > 0 {
+		m.Breakdown.Skills = skillTotal / float64(len(job.Skills))
+		m.Breakdown.Interest = float64(interests) / float64(len(job.Skills))
+	}
+
+	m.Breakdown.Location, m.Location = locationFit(p.Places, job)
+
+	if job.PublishedAt != nil {
+		age := now.Sub(*job.PublishedAt)
+		m.Breakdown.Freshness = math.Max(0, 1-float64(age)/float64(freshnessWindow))
+	}
+
+	score := skillsShare*m.Breakdown.Skills +
+		locationShare*m.Breakdown.Location +
+		interestShare*m.Breakdown.Interest +
+		freshnessShare*m.Breakdown.Freshness
+	m.Score = math.Round(score*1000) / 10
+	return m
+}
+
+// locationFit rates the best pairing of a job location with a preferred place
+// and describes it. Without preferences every job gets a neutral rating.
+func locationFit(places []geo.Place, job model.Job) (float64, string) {
+	if job.WorkMode == model.WorkRemote {
+		if len(job.RemoteCountries) == 0 || len(places) == 0 {
+			return 1, "remote"
+		}
+		for _, place := range places {
+			for _, country := range job.RemoteCountries {
+				if country == place.Country {
+					return 1, "remote from " + country
+				}
+			}
+		}
+		return 0.2, "remote, but not from your country"
+	}
+	if len(places) == 0 {
+		return 0.5, "no location preference"
+	}
+
+	best, reason := 0.0, "outside your preferred locations"
+	for _, location := range job.Locations {
+		for _, place := range places {
+			distance := math.Inf(1)
+			if location.Latitude != nil && location.Longitude != nil {
+				distance = geo.DistanceKm(*location.Latitude, *location.Longitude, place.Latitude, place.Longitude)
+			}
+			score, why := 0.0, ""
+			switch {
+			case distance <= nearKm:
+				score, why = 1, "near "+place.City
+			case distance <= regionKm:
+				score, why = 0.6, fmt.Sprintf("within %d km of %s", regionKm, place.City)
+			case location.Country != "" && location.Country == place.Country:
+				score, why = 0.4, "in "+place.Country
+			}
+			if score > best {
+				best, reason = score, why
+			}
+		}
+	}
+	return best, reason
+}
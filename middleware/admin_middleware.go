@@ -0,0 +1,30 @@
+package middleware
+
+import (
+	"crypto/subtle"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sahilq312/workly/initializer"
+)
+
+// AdminAuth allows requests carrying the X-Admin-Key header that matches ADMIN_API_KEY.
+// Admin routes are disabled entirely when no key is configured.
+func AdminAuth(c *gin.Context) {
+	secret := initializer.Config.Admin.APIKey
+	if secret == "" {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Admin access is not configured"})
+		c.Abort()
+		return
+	}
+
+	key := c.GetHeader("X-Admin-Key")
+	if key == "" || subtle.ConstantTimeCompare([]byte(key), []byte(secret)) != 1 {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid admin key"})
+		c.Abort()
+		return
+	}
+
+	c.Set("admin", true)
+	c.Next()
+}
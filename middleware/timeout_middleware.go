@@ -0,0 +1,24 @@
+package middleware
+
+import (
+	"context"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sahilq312/workly/initializer"
+)
+
+// StatementTimeout bounds the request context so database calls made through
+// initializer.Reader and initializer.Writer are cancelled once it expires.
+// Postgres enforces the same limit on every statement, so calls made through
+// initializer.DB directly are bounded too.
+func StatementTimeout(c *gin.Context) {
+	timeout := initializer.Config.Database.StatementTimeout
+	if timeout <= 0 {
+		c.Next()
+		return
+	}
+	ctx, cancel := context.WithTimeout(c.Request.Context(), timeout)
+	defer cancel()
+	c.Request = c.Request.WithContext(ctx)
+	c.Next()
+}
@@ -0,0 +1,84 @@
+package main
+
+import (
+	"log"
+
+	"github.com/sahilq312/workly/initializer"
+	"github.com/sahilq312/workly/model"
+)
+
+// Free-form statuses written before the application state machine existed
+var legacyApplicationStatuses = map[string]string{
+	"pending":   model.ApplicationApplied,
+	"applied":   model.ApplicationApplied,
+	"reviewed":  model.ApplicationScreening,
+	"screening": model.ApplicationScreening,
+	"interview": model.ApplicationInterview,
+	"offer":     model.ApplicationOffer,
+	"accepted":  model.ApplicationHired,
+	"hired":     model.ApplicationHired,
+	"rejected":  model.ApplicationRejected,
+	"withdrawn": model.ApplicationWithdrawn,
+}
+
+// migrateApplicationStatuses maps legacy statuses onto the state machine,
+// falling back to applied for anything unrecognised. It runs before
+// AutoMigrate so the dedupe and the unique index on active applications see
+// the same statuses.
+func migrateApplicationStatuses() error {
+	db := initializer.DB
+	if !db.Migrator().HasTable(&model.Application{}) {
+		return nil
+	}
+	for legacy, status := range legacyApplicationStatuses {
+		result := db.Model(&model.Application{}).Unscoped().
+			Where("LOWER(TRIM(status)) = ? AND status <> ?", legacy, status).
+			UpdateColumn("status", status)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected > 0 {
+			log.Printf("application statuses: moved %d from %q to %s", result.RowsAffected, legacy, status)
+		}
+	}
+	result := db.Model(&model.Application{}).Unscoped().
+		Where("status IS NULL OR status NOT IN ?", model.ApplicationStatuses).
+		UpdateColumn("status", model.ApplicationApplied)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected > 0 {
+		log.Printf("application statuses: reset %d unrecognised statuses to %s", result.RowsAffected, model.ApplicationApplied)
+	}
+	return nil
+}
+
+// backfillApplicationHistory gives every application without history a first
+// entry for its current state
+func backfillApplicationHistory() error {
+	return initializer.DB.Exec(`INSERT INTO application_status_changes (created_at, application_id, from_status, to_status, actor_type, actor_id, note)
+		SELECT COALESCE(applied_at, created_at), id, '', status, ?, 0, ''
+		FROM applications
+		WHERE NOT EXISTS (SELECT 1 FROM application_status_changes WHERE application_status_changes.application_id = applications.id)`,
+		model.ActorSystem).Error
+}
+
+// dedupeApplications soft-deletes all but the first active application of
+// each user and job, so the unique index on active applications can be
+// built. It uses the index's own predicate and expects statuses to have been
+// migrated already.
+func dedupeApplications() error {
+	if !initializer.DB.Migrator().HasTable(&model.Application{}) {
+		return nil
+	}
+	active := "deleted_at IS NULL AND status NOT IN ('rejected', 'withdrawn')"
+	result := initializer.DB.Exec(`UPDATE applications SET deleted_at = NOW()
+		WHERE ` + active + ` AND id NOT IN (SELECT MIN(id) FROM applications WHERE ` + active + ` GROUP BY user_id, job_id)`)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected > 0 {
+		log.Printf("applications: removed %d duplicate applications", result.RowsAffected)
+	}
+	return nil
+}
@@ -0,0 +1,42 @@
+package main
+
+import (
+	"github.com/sahilq312/workly/initializer"
+	"github.com/sahilq312/workly/model"
+	"github.com/sahilq312/workly/utils"
+)
+
+// Categories available out of the box; admins can add, rename or remove them
+var defaultJobCategories = []string{
+	"Engineering",
+	"Data Science",
+	"DevOps & Infrastructure",
+	"Design",
+	"Product",
+	"Quality Assurance",
+	"Marketing",
+	"Sales",
+	"Customer Support",
+	"Operations",
+	"Finance",
+	"People & HR",
+	"Legal",
+}
+
+// seedJobCategories creates the default job categories on a fresh install.
+// Once any category exists the admins own the list, so defaults they deleted
+// or renamed are not brought back.
+func seedJobCategories() error {
+	var count int64
+	if err := initializer.DB.Unscoped().Model(&model.JobCategory{}).Count(&count).Error; err != nil {
+		return err
+	}
+	if count > 0 {
+		return nil
+	}
+	categories := make([]model.JobCategory, len(defaultJobCategories))
+	for i, name := range defaultJobCategories {
+		categories[i] = model.JobCategory{Name: name, Slug: utils.Slugify(name)}
+	}
+	return initializer.DB.Create(&categories).Error
+}
@@ -0,0 +1,45 @@
+package main
+
+import (
+	"log"
+
+	"github.com/sahilq312/workly/helpers"
+	"github.com/sahilq312/workly/initializer"
+	"github.com/sahilq312/workly/model"
+	"gorm.io/gorm"
+)
+
+// backfillJobLocations geocodes the free-form location of jobs that have no
+// structured locations yet. Text that cannot be resolved is left as is.
+func backfillJobLocations() error {
+	var jobs []model.Job
+	return initializer.DB.
+		Where("location <> '' AND NOT EXISTS (SELECT 1 FROM job_locations WHERE job_locations.job_id = jobs.id)").
+		FindInBatches(&jobs, 500, func(_ *gorm.DB, batch int) error {
+			resolved := 0
+			for _, job := range jobs {
+				placement, err := helpers.ResolveJobPlacement("", nil, job.Location, nil)
+				if err != nil {
+					continue
+				}
+				err = initializer.DB.Transaction(func(tx *gorm.DB) error {
+					if err := tx.Model(&model.Job{}).Where("id = ?", job.ID).UpdateColumn("work_mode", placement.WorkMode).Error; err != nil {
+						return err
+					}
+					for i := range placement.Locations {
+						placement.Locations[i].JobID = job.ID
+					}
+					if len(placement.Locations) == 0 {
+						return nil
+					}
+					return tx.Create(&placement.Locations).Error
+				})
+				if err != nil {
+					return err
+				}
+				resolved++
+			}
+			log.Printf("location backfill: batch %d resolved %d/%d jobs", batch, resolved, len(jobs))
+			return nil
+		}).Error
+}
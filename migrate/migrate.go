@@ -1,27 +1,54 @@
 package main
 
 import (
+	"log"
+
 	"github.com/sahilq312/workly/initializer"
 	"github.com/sahilq312/workly/model"
 )
 
 func init() {
-	initializer.LoadEnvVariale()
+	initializer.LoadDatabaseConfig()
+	// Backfills and index builds may take longer than any request
+	initializer.Config.Database.StatementTimeout = 0
 	initializer.ConnectPostgresDatabase()
 }
 
 func main() {
-	initializer.DB.AutoMigrate(
-		&model.User{},
-		&model.Experience{},
-		&model.Education{},
-		&model.Post{},
-		&model.Company{},
-		&model.Job{},
-		&model.Skill{},
-		&model.UserFollow{},
-		&model.Like{},
-		&model.Comment{},
-		&model.Application{},
-	)
+	// Jobs created before the lifecycle existed were all public
+	publishExistingJobs := initializer.DB.Migrator().HasTable(&model.Job{}) &&
+		!initializer.DB.Migrator().HasColumn(&model.Job{}, "Status")
+
+	if err := migrateApplicationStatuses(); err != nil {
+		log.Fatalf("application status migration failed: %v", err)
+	}
+	if err := dedupeApplications(); err != nil {
+		log.Fatalf("application dedupe failed: %v", err)
+	}
+	if err := initializer.DB.AutoMigrate(model.AllModels()...); err != nil {
+		log.Fatalf("migration failed: %v", err)
+	}
+	if publishExistingJobs {
+		if err := initializer.DB.Exec("UPDATE jobs SET status = ?, published_at = created_at", model.JobPublished).Error; err != nil {
+			log.Fatalf("publishing existing jobs failed: %v", err)
+		}
+	}
+	if err := backfillApplicationHistory(); err != nil {
+		log.Fatalf("application history backfill failed: %v", err)
+	}
+	if err := migrateJobSearch(); err != nil {
+		log.Fatalf("job search migration failed: %v", err)
+	}
+	if err := migrateSkills(); err != nil {
+		log.Fatalf("skill taxonomy migration failed: %v", err)
+	}
+	if err := seedJobCategories(); err != nil {
+		log.Fatalf("job category migration failed: %v", err)
+	}
+	if err := backfillCompensation(); err != nil {
+		log.Fatalf("salary backfill failed: %v", err)
+	}
+	if err := backfillJobLocations(); err != nil {
+		log.Fatalf("location backfill failed: %v", err)
+	}
 }
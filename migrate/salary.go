@@ -0,0 +1,41 @@
+package main
+
+import (
+	"log"
+
+	"github.com/sahilq312/workly/helpers"
+	"github.com/sahilq312/workly/initializer"
+	"github.com/sahilq312/workly/model"
+	"gorm.io/gorm"
+)
+
+// backfillCompensation parses the legacy free-form salary of jobs that have no
+// structured range yet. Strings that cannot be parsed are left untouched.
+func backfillCompensation() error {
+	var jobs []model.Job
+	return initializer.DB.
+		Where("salary <> '' AND salary_min IS NULL AND salary_max IS NULL").
+		FindInBatches(&jobs, 500, func(_ *gorm.DB, batch int) error {
+			parsed := 0
+			for _, job := range jobs {
+				comp, ok := helpers.ParseSalary(job.Salary)
+				if !ok {
+					continue
+				}
+				if err := initializer.DB.Model(&model.Job{}).Where("id = ?", job.ID).UpdateColumns(map[string]interface{}{
+					"salary_min":        comp.Min,
+					"salary_max":        comp.Max,
+					"salary_currency":   comp.Currency,
+					"salary_period":     comp.Period,
+					"salary_equity":     comp.Equity,
+					"salary_annual_min": comp.AnnualMin,
+					"salary_annual_max": comp.AnnualMax,
+				}).Error; err != nil {
+					return err
+				}
+				parsed++
+			}
+			log.Printf("salary backfill: batch %d parsed %d/%d jobs", batch, parsed, len(jobs))
+			return nil
+		}).Error
+}
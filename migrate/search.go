@@ -0,0 +1,94 @@
+package main
+
+import (
+	"github.com/sahilq312/workly/initializer"
+)
+
+// jobSearchSQL maintains jobs.search_vector with triggers so every write path
+// (API, seed, imports, raw SQL) keeps it current. Weights: title A, skill
+// names and their aliases B, company name and location C, description D.
+var jobSearchSQL = []string{
+	`ALTER TABLE jobs ADD COLUMN IF NOT EXISTS search_vector tsvector`,
+
+	`CREATE OR REPLACE FUNCTION jobs_search_vector_update() RETURNS trigger AS $$
+	BEGIN
+		NEW.search_vector :=
+			setweight(to_tsvector('english', coalesce(NEW.title, '')), 'A') ||
+			setweight(to_tsvector('english', coalesce((
+				SELECT string_agg(s.name || ' ' || coalesce((
+					SELECT string_agg(a.name, ' ') FROM skill_aliases a
+					WHERE a.skill_id = s.id AND a.deleted_at IS NULL
+				), ''), ' ')
+				FROM job_skills js JOIN skills s ON s.id = js.skill_id
+				WHERE js.job_id = NEW.id
+			), '')), 'B') ||
+			setweight(to_tsvector('english',
+				coalesce((SELECT c.name FROM companies c WHERE c.id = NEW.company_id), '') || ' ' ||
+				coalesce(NEW.location, '')), 'C') ||
+			setweight(to_tsvector('english', coalesce(NEW.description, '')), 'D');
+		RETURN NEW;
+	END
+	$$ LANGUAGE plpgsql`,
+
+	`DROP TRIGGER IF EXISTS jobs_search_vector_trigger ON jobs`,
+	`CREATE TRIGGER jobs_search_vector_trigger BEFORE INSERT OR UPDATE ON jobs
+		FOR EACH ROW EXECUTE FUNCTION jobs_search_vector_update()`,
+
+	// Touching the job row re-runs the trigger above
+	`CREATE OR REPLACE FUNCTION job_skills_search_refresh() RETURNS trigger AS $$
+	BEGIN
+		UPDATE jobs SET search_vector = NULL WHERE id = COALESCE(NEW.job_id, OLD.job_id);
+		RETURN NULL;
+	END
+	$$ LANGUAGE plpgsql`,
+	`DROP TRIGGER IF EXISTS job_skills_search_trigger ON job_skills`,
+	`CREATE TRIGGER job_skills_search_trigger AFTER INSERT OR DELETE ON job_skills
+		FOR EACH ROW EXECUTE FUNCTION job_skills_search_refresh()`,
+
+	`CREATE OR REPLACE FUNCTION skills_search_refresh() RETURNS trigger AS $$
+	BEGIN
+		UPDATE jobs SET search_vector = NULL
+		WHERE id IN (SELECT job_id FROM job_skills WHERE skill_id = NEW.id);
+		RETURN NULL;
+	END
+	$$ LANGUAGE plpgsql`,
+	`DROP TRIGGER IF EXISTS skills_search_trigger ON skills`,
+	`CREATE TRIGGER skills_search_trigger AFTER UPDATE OF name ON skills
+		FOR EACH ROW EXECUTE FUNCTION skills_search_refresh()`,
+
+	`CREATE OR REPLACE FUNCTION skill_aliases_search_refresh() RETURNS trigger AS $$
+	BEGIN
+		UPDATE jobs SET search_vector = NULL
+		WHERE id IN (SELECT job_id FROM job_skills WHERE skill_id IN (NEW.skill_id, OLD.skill_id));
+		RETURN NULL;
+	END
+	$$ LANGUAGE plpgsql`,
+	`DROP TRIGGER IF EXISTS skill_aliases_search_trigger ON skill_aliases`,
+	`CREATE TRIGGER skill_aliases_search_trigger AFTER INSERT OR UPDATE OR DELETE ON skill_aliases
+		FOR EACH ROW EXECUTE FUNCTION skill_aliases_search_refresh()`,
+
+	`CREATE OR REPLACE FUNCTION companies_search_refresh() RETURNS trigger AS $$
+	BEGIN
+		UPDATE jobs SET search_vector = NULL WHERE company_id = NEW.id;
+		RETURN NULL;
+	END
+	$$ LANGUAGE plpgsql`,
+	`DROP TRIGGER IF EXISTS companies_search_trigger ON companies`,
+	`CREATE TRIGGER companies_search_trigger AFTER UPDATE OF name ON companies
+		FOR EACH ROW EXECUTE FUNCTION companies_search_refresh()`,
+
+	`CREATE INDEX IF NOT EXISTS idx_jobs_search_vector ON jobs USING GIN (search_vector)`,
+
+	// Backfill rows written before the trigger existed
+	`UPDATE jobs SET search_vector = NULL WHERE search_vector IS NULL`,
+}
+
+// migrateJobSearch installs the full-text search column, triggers and index
+func migrateJobSearch() error {
+	for _, stmt := range jobSearchSQL {
+		if err := initializer.DB.Exec(stmt).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,192 @@
+package main
+
+import (
+	"errors"
+	"log"
+
+	"github.com/sahilq312/workly/helpers"
+	"github.com/sahilq312/workly/initializer"
+	"github.com/sahilq312/workly/model"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// taxonomyEntry is a canonical skill with its category and other names
+type taxonomyEntry struct {
+	Name     string
+	Category string
+	Aliases  []string
+}
+
+// Top-level categories, with their parent where they nest
+var skillCategories = []struct{ Name, Parent string }{
+	{"Languages", ""},
+	{"Frameworks", ""},
+	{"Frontend", "Frameworks"},
+	{"Backend", "Frameworks"},
+	{"Databases", ""},
+	{"Cloud & DevOps", ""},
+	{"Data & ML", ""},
+	{"Design", ""},
+	{"Practices", ""},
+}
+
+// defaultTaxonomy is the built-in skill registry. Aliases are only other
+// spellings of the same skill; related but different skills stay separate.
+var defaultTaxonomy = []taxonomyEntry{
+	{"Go", "Languages", []string{"Golang"}},
+	{"JavaScript", "Languages", []string{"JS", "ECMAScript"}},
+	{"TypeScript", "Languages", []string{"TS"}},
+	{"Python", "Languages", []string{"Python3", "Py"}},
+	{"Java", "Languages", nil},
+	{"Kotlin", "Languages", nil},
+	{"Swift", "Languages", nil},
+	{"Rust", "Languages", []string{"Rustlang"}},
+	{"C++", "Languages", []string{"CPP"}},
+	{"C#", "Languages", []string{"CSharp", "C Sharp"}},
+	{"Ruby", "Languages", nil},
+	{"PHP", "Languages", nil},
+	{"Scala", "Languages", nil},
+	{"SQL", "Languages", nil},
+	{"React", "Frontend", []string{"ReactJS", "React.js"}},
+	{"Vue.js", "Frontend", []string{"Vue", "VueJS"}},
+	{"Angular", "Frontend", []string{"AngularJS"}},
+	{"Next.js", "Frontend", []string{"NextJS"}},
+	{"Node.js", "Backend", []string{"Node", "NodeJS"}},
+	{"Django", "Backend", nil},
+	{"Ruby on Rails", "Backend", []string{"Rails", "RoR"}},
+	{"Spring Boot", "Backend", nil},
+	{"GraphQL", "Backend", nil},
+	{"PostgreSQL", "Databases", []string{"Postgres", "PSQL"}},
+	{"MySQL", "Databases", nil},
+	{"MongoDB", "Databases", []string{"Mongo"}},
+	{"Redis", "Databases", nil},
+	{"Elasticsearch", "Databases", []string{"Elastic Search"}},
+	{"AWS", "Cloud & DevOps", []string{"Amazon Web Services"}},
+	{"Google Cloud", "Cloud & DevOps", []string{"GCP", "Google Cloud Platform"}},
+	{"Azure", "Cloud & DevOps", []string{"Microsoft Azure"}},
+	{"Docker", "Cloud & DevOps", nil},
+	{"Kubernetes", "Cloud & DevOps", []string{"K8s"}},
+	{"Terraform", "Cloud & DevOps", nil},
+	{"CI/CD", "Cloud & DevOps", []string{"Continuous Integration"}},
+	{"Machine Learning", "Data & ML", []string{"ML"}},
+	{"Data Analysis", "Data & ML", []string{"Data Analytics"}},
+	{"Apache Spark", "Data & ML", []string{"Spark", "PySpark"}},
+	{"Figma", "Design", nil},
+	{"UI/UX Design", "Design", nil},
+	{"Agile", "Practices", nil},
+	{"Testing", "Practices", nil},
+}
+
+// migrateSkills normalizes existing skills, merges duplicates that only
+// differ in case or spacing, enforces unique keys and, on a fresh install,
+// installs the built-in taxonomy. Once categories exist the admins own the
+// taxonomy, so skills and aliases they changed or deleted are left alone.
+func migrateSkills() error {
+	return initializer.DB.Transaction(func(tx *gorm.DB) error {
+		var skills []model.Skill
+		if err := tx.Order("id").Find(&skills).Error; err != nil {
+			return err
+		}
+		byKey := map[string][]model.Skill{}
+		var keys []string
+		for _, skill := range skills {
+			key := model.SkillKey(skill.Name)
+			if skill.Key != key {
+				if err := tx.Model(&model.Skill{}).Where("id = ?", skill.ID).UpdateColumn("key", key).Error; err != nil {
+					return err
+				}
+				skill.Key = key
+			}
+			if len(byKey[key]) == 0 {
+				keys = append(keys, key)
+			}
+			byKey[key] = append(byKey[key], skill)
+		}
+		for _, key := range keys {
+			if group := byKey[key]; len(group) > 1 {
+				log.Printf("skills: merging %d duplicates of %q", len(group)-1, group[0].Name)
+				if err := helpers.MergeSkills(tx, group[0], group[1:]); err != nil {
+					return err
+				}
+			}
+		}
+		if err := tx.Exec("CREATE UNIQUE INDEX IF NOT EXISTS idx_skills_key ON skills (key) WHERE deleted_at IS NULL").Error; err != nil {
+			return err
+		}
+
+		var count int64
+		if err := tx.Unscoped().Model(&model.SkillCategory{}).Count(&count).Error; err != nil {
+			return err
+		}
+		if count > 0 {
+			return nil
+		}
+		categories := map[string]model.SkillCategory{}
+		for _, c := range skillCategories {
+			category := model.SkillCategory{Name: c.Name}
+			if c.Parent != "" {
+				parentID := categories[c.Parent].ID
+				category.ParentID = &parentID
+			}
+			if err := tx.Where(model.SkillCategory{Name: c.Name}).Attrs(category).FirstOrCreate(&category).Error; err != nil {
+				return err
+			}
+			categories[c.Name] = category
+		}
+		for _, entry := range defaultTaxonomy {
+			if err := installTaxonomyEntry(tx, entry, categories[entry.Category].ID); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// installTaxonomyEntry creates the skill of entry, or files an existing skill
+// of the same name under its category, and adds the aliases no skill or
+// alias uses yet. Existing skills are never merged.
+func installTaxonomyEntry(tx *gorm.DB, entry taxonomyEntry, categoryID uint) error {
+	key := model.SkillKey(entry.Name)
+	var skill model.Skill
+	err := tx.Where("key = ?", key).First(&skill).Error
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		skill = model.Skill{Name: entry.Name, Key: key, CategoryID: &categoryID}
+		if err := tx.Create(&skill).Error; err != nil {
+			return err
+		}
+	case err != nil:
+		return err
+	default:
+		// Only fix up skills entered in lower case
+		updates := map[string]interface{}{}
+		if skill.Name == skill.Key {
+			updates["name"] = entry.Name
+		}
+		if skill.CategoryID == nil {
+			updates["category_id"] = categoryID
+		}
+		if len(updates) > 0 {
+			if err := tx.Model(&model.Skill{}).Where("id = ?", skill.ID).Updates(updates).Error; err != nil {
+				return err
+			}
+		}
+	}
+
+	for _, name := range entry.Aliases {
+		aliasKey := model.SkillKey(name)
+		var taken int64
+		if err := tx.Unscoped().Model(&model.Skill{}).Where("key = ?", aliasKey).Count(&taken).Error; err != nil {
+			return err
+		}
+		if taken > 0 {
+			continue
+		}
+		alias := model.SkillAlias{SkillID: skill.ID, Name: model.CleanSkillName(name), Key: aliasKey}
+		if err := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&alias).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
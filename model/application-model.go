@@ -6,12 +6,73 @@ import (
 	"gorm.io/gorm"
 )
 
+// Application states
+const (
+	ApplicationApplied   = "applied"
+	ApplicationScreening = "screening"
+	ApplicationInterview = "interview"
+	ApplicationOffer     = "offer"
+	ApplicationHired     = "hired"
+	ApplicationRejected  = "rejected"
+	ApplicationWithdrawn = "withdrawn"
+)
+
+// ApplicationStatuses lists every application state in pipeline order
+var ApplicationStatuses = []string{
+	ApplicationApplied,
+	ApplicationScreening,
+	ApplicationInterview,
+	ApplicationOffer,
+	ApplicationHired,
+	ApplicationRejected,
+	ApplicationWithdrawn,
+}
+
+// applicationTransitions lists the states an application may move to from
+// each state. Hired, rejected and withdrawn are final.
+var applicationTransitions = map[string][]string{
+	ApplicationApplied:   {ApplicationScreening, ApplicationInterview, ApplicationRejected, ApplicationWithdrawn},
+	ApplicationScreening: {ApplicationInterview, ApplicationOffer, ApplicationRejected, ApplicationWithdrawn},
+	ApplicationInterview: {ApplicationOffer, ApplicationRejected, ApplicationWithdrawn},
+	ApplicationOffer:     {ApplicationHired, ApplicationRejected, ApplicationWithdrawn},
+}
+
+// IsTerminalApplicationStatus reports whether an application in the given
+// state can no longer move
+func IsTerminalApplicationStatus(status string) bool {
+	_, ok := applicationTransitions[status]
+	return !ok
+}
+
+// CanTransitionApplication reports whether an application may move from one
+// state to another
+func CanTransitionApplication(from, to string) bool {
+	for _, allowed := range applicationTransitions[from] {
+		if allowed == to {
+			return true
+		}
+	}
+	return false
+}
+
+// Application is a user's application to a job. KnockedOut is set when a
+// screening answer rejected it automatically. Status only changes along
+// applicationTransitions, and every change is kept in History. StageID
+// places it in the job's pipeline, when the job has one. A user has at most
+// one active application per job; once rejected or withdrawn they may apply
+// again, subject to the company's re-apply cool-down.
 type Application struct {
 	gorm.Model
-	UserID    uint      `json:"user_id" gorm:"not null"`
-	JobID     uint      `json:"job_id" gorm:"not null"`
-	Status    string    `json:"status" gorm:"default:'Pending'"`
-	AppliedAt time.Time `json:"applied_at" gorm:"autoCreateTime"`
-	User      User      `json:"user" gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE"`
-	Job       Job       `json:"job" gorm:"foreignKey:JobID;constraint:OnDelete:CASCADE"`
+	UserID     uint                      `json:"user_id" gorm:"not null;uniqueIndex:idx_applications_active_user_job,where:deleted_at IS NULL AND status NOT IN ('rejected'\\, 'withdrawn')"`
+	JobID      uint                      `json:"job_id" gorm:"not null;uniqueIndex:idx_applications_active_user_job"`
+	Status     string                    `json:"status" gorm:"default:'applied'"`
+	StageID    *uint                     `json:"stage_id" gorm:"index"`
+	KnockedOut bool                      `json:"knocked_out" gorm:"not null;default:false"`
+	AppliedAt  time.Time                 `json:"applied_at" gorm:"autoCreateTime"`
+	User       User                      `json:"user" gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE"`
+	Job        Job                       `json:"job" gorm:"foreignKey:JobID;constraint:OnDelete:CASCADE"`
+	Stage      *PipelineStage            `json:"stage,omitempty" gorm:"foreignKey:StageID;constraint:OnDelete:SET NULL"`
+	Answers    []ScreeningAnswer         `json:"answers,omitempty" gorm:"foreignKey:ApplicationID;constraint:OnDelete:CASCADE"`
+	Documents  []Document                `json:"documents,omitempty" gorm:"foreignKey:ApplicationID;constraint:OnDelete:CASCADE"`
+	History    []ApplicationStatusChange `json:"history,omitempty" gorm:"foreignKey:ApplicationID;constraint:OnDelete:CASCADE"`
 }
@@ -0,0 +1,28 @@
+package model
+
+import "time"
+
+// Application history events
+const (
+	HistoryStatusChange = "status_change"
+	HistoryEdit         = "edit"
+)
+
+// ApplicationStatusChange records one event in an application's history: a
+// move between states or pipeline stages, or the candidate editing their
+// documents and answers, with who did it and why. The first entry of an
+// application has an empty FromStatus. Stage names are copied so the history
+// survives pipeline edits. Like AuditLog, rows are append-only.
+type ApplicationStatusChange struct {
+	ID            uint      `json:"id" gorm:"primarykey"`
+	CreatedAt     time.Time `json:"created_at"`
+	ApplicationID uint      `json:"application_id" gorm:"not null;index:idx_application_status_changes_application"`
+	Event         string    `json:"event" gorm:"not null;default:'status_change'"`
+	FromStatus    string    `json:"from_status"`
+	ToStatus      string    `json:"to_status" gorm:"not null"`
+	FromStage     string    `json:"from_stage,omitempty"`
+	ToStage       string    `json:"to_stage,omitempty"`
+	ActorType     string    `json:"actor_type" gorm:"not null"`
+	ActorID       uint      `json:"actor_id"`
+	Note          string    `json:"note,omitempty"`
+}
@@ -0,0 +1,51 @@
+package model
+
+import "time"
+
+// Principal types recorded on audit log entries
+const (
+	ActorUser    = "user"
+	ActorCompany = "company"
+	ActorAdmin   = "admin"
+	ActorSystem  = "system"
+)
+
+// AuditLog is an append-only record of a security-relevant or hiring action.
+// Rows are never updated or deleted, so it intentionally has no gorm.Model.
+type AuditLog struct {
+	ID         uint      `json:"id" gorm:"primarykey"`
+	CreatedAt  time.Time `json:"created_at" gorm:"index"`
+	ActorID    uint      `json:"actor_id" gorm:"index:idx_audit_actor"`
+	ActorType  string    `json:"actor_type" gorm:"not null;index:idx_audit_actor"`
+	Action     string    `json:"action" gorm:"not null;index"`
+	EntityType string    `json:"entity_type" gorm:"index:idx_audit_entity"`
+	EntityID   uint      `json:"entity_id" gorm:"index:idx_audit_entity"`
+	Before     JSON      `json:"before,omitempty" gorm:"type:jsonb"`
+	After      JSON      `json:"after,omitempty" gorm:"type:jsonb"`
+	IP         string    `json:"ip"`
+	UserAgent  string    `json:"user_agent"`
+	Method     string    `json:"method"`
+	Path       string    `json:"path"`
+}
+
+// Audit actions
+const (
+	AuditUserLogin          = "user.login"
+	AuditUserLoginFailed    = "user.login_failed"
+	AuditCompanyLogin       = "company.login"
+	AuditCompanyLoginFailed = "company.login_failed"
+	AuditCompanyUpdate      = "company.update"
+	AuditJobCreate          = "job.create"
+	AuditJobUpdate          = "job.update"
+	AuditJobDelete          = "job.delete"
+	AuditJobStatus          = "job.status_change"
+	AuditJobPipeline        = "job.pipeline_update"
+	AuditApplicationStatus  = "application.status_change"
+	AuditInterviewCreate    = "interview.create"
+	AuditInterviewBook      = "interview.book"
+	AuditInterviewMoved     = "interview.reschedule"
+	AuditInterviewCancel    = "interview.cancel"
+	AuditSkillUpdate        = "skill.update"
+	AuditSkillMerge         = "skill.merge"
+	AuditAdminQuery         = "admin.audit_query"
+)
@@ -2,12 +2,16 @@ package model
 
 import "gorm.io/gorm"
 
+// Company is an employer account. ReapplyCooldownDays is how long a rejected
+// candidate must wait before applying to the same job again; a negative
+// value means never.
 type Company struct {
 	gorm.Model
-	Name     string `json:"name" gorm:"not null"`
-	Logo     string `json:"logo"`
-	Email    string `json:"email" gorm:"not null"`
-	Password string `json:"-" gorm:"not null"`
-	Address  string `json:"address,omitempty"`
-	Jobs     []Job  `json:"jobs" gorm:"foreignKey:CompanyID;constraint:OnDelete:CASCADE"`
+	Name                string `json:"name" gorm:"not null"`
+	Logo                string `json:"logo"`
+	Email               string `json:"email" gorm:"not null"`
+	Password            string `json:"-" gorm:"not null"`
+	Address             string `json:"address,omitempty"`
+	ReapplyCooldownDays int    `json:"reapply_cooldown_days" gorm:"not null;default:90"`
+	Jobs                []Job  `json:"jobs" gorm:"foreignKey:CompanyID;constraint:OnDelete:CASCADE"`
 }
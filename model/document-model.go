@@ -0,0 +1,23 @@
+package model
+
+import "gorm.io/gorm"
+
+// Document kinds
+const (
+	DocumentResume      = "resume"
+	DocumentCoverLetter = "cover_letter"
+)
+
+// Document is a file attached to an application. The file itself lives in
+// storage under StorageKey; URL is a signed download link filled in for the
+// principal viewing it.
+type Document struct {
+	gorm.Model
+	ApplicationID uint   `json:"application_id" gorm:"not null;index"`
+	Kind          string `json:"kind" gorm:"not null"`
+	FileName      string `json:"file_name" gorm:"not null"`
+	ContentType   string `json:"content_type" gorm:"not null"`
+	Size          int64  `json:"size" gorm:"not null"`
+	StorageKey    string `json:"-" gorm:"not null;uniqueIndex"`
+	URL           string `json:"url,omitempty" gorm:"-"`
+}
@@ -0,0 +1,63 @@
+package model
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Interview states
+const (
+	InterviewProposed  = "proposed"
+	InterviewScheduled = "scheduled"
+	InterviewCancelled = "cancelled"
+)
+
+// Interview is a meeting with the candidate of an application. The company
+// offers Slots; once the candidate books one, StartsAt is set and the
+// interview is scheduled. Sequence counts the changes to invites already
+// sent, as iCalendar requires. Timezone is the IANA zone times are shown in.
+type Interview struct {
+	gorm.Model
+	ApplicationID   uint            `json:"application_id" gorm:"not null;index"`
+	Application     Application     `json:"-" gorm:"foreignKey:ApplicationID;constraint:OnDelete:CASCADE"`
+	Title           string          `json:"title" gorm:"not null"`
+	DurationMinutes int             `json:"duration_minutes" gorm:"not null"`
+	Timezone        string          `json:"timezone" gorm:"not null"`
+	Location        string          `json:"location,omitempty"`
+	VideoURL        string          `json:"video_url,omitempty"`
+	Instructions    string          `json:"instructions,omitempty"`
+	Status          string          `json:"status" gorm:"not null;default:'proposed';index"`
+	SlotID          *uint           `json:"slot_id"`
+	StartsAt        *time.Time      `json:"starts_at" gorm:"index"`
+	Sequence        int             `json:"sequence" gorm:"not null;default:0"`
+	CancelReason    string          `json:"cancel_reason,omitempty"`
+	Slots           []InterviewSlot `json:"slots" gorm:"foreignKey:InterviewID;constraint:OnDelete:CASCADE"`
+	Interviewers    []Interviewer   `json:"interviewers" gorm:"many2many:interview_interviewers"`
+}
+
+// EndsAt is when a scheduled interview ends
+func (i Interview) EndsAt() time.Time {
+	if i.StartsAt == nil {
+		return time.Time{}
+	}
+	return i.StartsAt.Add(time.Duration(i.DurationMinutes) * time.Minute)
+}
+
+// InterviewSlot is a start time offered to the candidate
+type InterviewSlot struct {
+	gorm.Model
+	InterviewID uint      `json:"interview_id" gorm:"not null;index"`
+	StartsAt    time.Time `json:"starts_at" gorm:"not null"`
+}
+
+// Interviewer is a member of a company's hiring team. FeedToken is the secret
+// part of the URL of their interview calendar feed.
+type Interviewer struct {
+	gorm.Model
+	CompanyID uint   `json:"company_id" gorm:"not null;index"`
+	Name      string `json:"name" gorm:"not null"`
+	Email     string `json:"email" gorm:"not null"`
+	FeedToken string `json:"-" gorm:"not null;uniqueIndex"`
+	FeedURL   string `json:"feed_url,omitempty" gorm:"-"`
+}
@@ -0,0 +1,37 @@
+package model
+
+import "gorm.io/gorm"
+
+// Seniority levels for jobs, from least to most senior
+const (
+	SeniorityIntern    = "intern"
+	SeniorityJunior    = "junior"
+	SeniorityMid       = "mid"
+	SenioritySenior    = "senior"
+	SeniorityLead      = "lead"
+	SeniorityPrincipal = "principal"
+	SeniorityExecutive = "executive"
+)
+
+// SeniorityLevels lists every seniority level in order
+var SeniorityLevels = []string{SeniorityIntern, SeniorityJunior, SeniorityMid, SenioritySenior, SeniorityLead, SeniorityPrincipal, SeniorityExecutive}
+
+// Employment types for jobs
+const (
+	EmploymentFullTime   = "full_time"
+	EmploymentPartTime   = "part_time"
+	EmploymentContract   = "contract"
+	EmploymentInternship = "internship"
+	EmploymentTemporary  = "temporary"
+)
+
+// EmploymentTypes lists every employment type
+var EmploymentTypes = []string{EmploymentFullTime, EmploymentPartTime, EmploymentContract, EmploymentInternship, EmploymentTemporary}
+
+// JobCategory is a job function such as Engineering or Design, managed by admins
+type JobCategory struct {
+	gorm.Model
+	Name        string `json:"name" gorm:"unique;not null"`
+	Slug        string `json:"slug" gorm:"uniqueIndex;not null"`
+	Description string `json:"description"`
+}
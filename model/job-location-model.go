@@ -0,0 +1,33 @@
+package model
+
+import "gorm.io/gorm"
+
+// Work modes for jobs
+const (
+	WorkOnsite = "onsite"
+	WorkHybrid = "hybrid"
+	WorkRemote = "remote"
+)
+
+// JobLocation is one of the places a job can be done from
+type JobLocation struct {
+	gorm.Model
+	JobID     uint     `json:"job_id" gorm:"not null;index"`
+	City      string   `json:"city"`
+	Region    string   `json:"region"`
+	Country   string   `json:"country" gorm:"size:2;index"`
+	Latitude  *float64 `json:"latitude" gorm:"index:idx_job_locations_coords"`
+	Longitude *float64 `json:"longitude" gorm:"index:idx_job_locations_coords"`
+}
+
+// Label returns a human-readable form such as "Berlin, DE"
+func (l JobLocation) Label() string {
+	label := l.City
+	if l.Country != "" {
+		if label != "" {
+			label += ", "
+		}
+		label += l.Country
+	}
+	return label
+}
@@ -1,17 +1,170 @@
 package model
 
 import (
+	"fmt"
+	"strings"
+	"time"
+
 	"gorm.io/gorm"
 )
 
+// Job posting states
+const (
+	JobDraft     = "draft"
+	JobPublished = "published"
+	JobPaused    = "paused"
+	JobClosed    = "closed"
+	JobExpired   = "expired"
+)
+
+// jobTransitions lists the states a job may move to from each state
+var jobTransitions = map[string][]string{
+	JobDraft:     {JobPublished, JobClosed},
+	JobPublished: {JobPaused, JobClosed, JobExpired},
+	JobPaused:    {JobPublished, JobClosed},
+	JobClosed:    {JobPublished},
+	JobExpired:   {JobPublished},
+}
+
+// CanTransitionJob reports whether a job may move from one state to another
+func CanTransitionJob(from, to string) bool {
+	for _, allowed := range jobTransitions[from] {
+		if allowed == to {
+			return true
+		}
+	}
+	return false
+}
+
+// Pay periods for Compensation
+const (
+	PayHourly  = "hourly"
+	PayMonthly = "monthly"
+	PayYearly  = "yearly"
+)
+
+// Multipliers used to turn a pay period into a yearly amount
+var annualFactor = map[string]int64{
+	PayHourly:  2080,
+	PayMonthly: 12,
+	PayYearly:  1,
+}
+
+// Job is a posting by a company. RemoteCountries lists the ISO country codes
+// remote candidates may work from; empty means anywhere. Seniority and
+// EmploymentType are empty when not specified. ExternalRef is the company's
+// own ID for the job (e.g. from its ATS), unique per company when set.
 type Job struct {
 	gorm.Model
-	Title        string        `json:"title" gorm:"not null"`
-	Description  string        `json:"description"`
-	Skills       []Skill       `json:"skills" gorm:"many2many:job_skills"`
-	Location     string        `json:"location"`
-	Salary       string        `json:"salary"`
-	CompanyID    uint          `json:"company_id"`
-	Company      Company       `json:"company" gorm:"foreignKey:CompanyID;constraint:OnDelete:SET NULL"`
-	Applications []Application `json:"applications" gorm:"foreignKey:JobID;constraint:OnDelete:CASCADE"`
+	Title           string              `json:"title" gorm:"not null"`
+	Description     string              `json:"description"`
+	Skills          []Skill             `json:"skills" gorm:"many2many:job_skills"`
+	Location        string              `json:"location"`
+	Locations       []JobLocation       `json:"locations" gorm:"foreignKey:JobID;constraint:OnDelete:CASCADE"`
+	WorkMode        string              `json:"work_mode" gorm:"not null;default:'onsite';index"`
+	RemoteCountries StringList          `json:"remote_countries" gorm:"type:text;not null;default:''"`
+	Salary          string              `json:"salary"`
+	Compensation    Compensation        `json:"compensation" gorm:"embedded;embeddedPrefix:salary_"`
+	CategoryID      *uint               `json:"category_id" gorm:"index"`
+	Category        *JobCategory        `json:"category,omitempty" gorm:"foreignKey:CategoryID;constraint:OnDelete:SET NULL"`
+	Seniority       string              `json:"seniority" gorm:"not null;default:'';index"`
+	EmploymentType  string              `json:"employment_type" gorm:"not null;default:'';index"`
+	Status          string              `json:"status" gorm:"not null;default:'draft';index"`
+	PublishedAt     *time.Time          `json:"published_at"`
+	ExpiresAt       *time.Time          `json:"expires_at" gorm:"index"`
+	CompanyID       uint                `json:"company_id" gorm:"uniqueIndex:idx_jobs_company_external_ref,where:external_ref <> '' AND deleted_at IS NULL"`
+	ExternalRef     string              `json:"external_ref" gorm:"not null;default:'';uniqueIndex:idx_jobs_company_external_ref"`
+	Company         Company             `json:"company" gorm:"foreignKey:CompanyID;constraint:OnDelete:SET NULL"`
+	Applications    []Application       `json:"applications" gorm:"foreignKey:JobID;constraint:OnDelete:CASCADE"`
+	Questions       []ScreeningQuestion `json:"questions,omitempty" gorm:"foreignKey:JobID;constraint:OnDelete:CASCADE"`
+	Stages          []PipelineStage     `json:"stages,omitempty" gorm:"foreignKey:JobID;constraint:OnDelete:CASCADE"`
+}
+
+// Compensation is the structured pay range of a job. Amounts are whole units
+// of Currency per Period; the annual columns are derived for filtering and sorting.
+type Compensation struct {
+	Min       *int64 `json:"min"`
+	Max       *int64 `json:"max"`
+	Currency  string `json:"currency" gorm:"size:3;index"`
+	Period    string `json:"period"`
+	Equity    bool   `json:"equity" gorm:"not null;default:false"`
+	Hidden    bool   `json:"hidden" gorm:"not null;default:false"`
+	AnnualMin *int64 `json:"-" gorm:"index"`
+	AnnualMax *int64 `json:"-" gorm:"index"`
+}
+
+// IsZero reports whether no pay range has been set
+func (c Compensation) IsZero() bool {
+	return c.Min == nil && c.Max == nil
+}
+
+// Normalize recomputes the yearly amounts from Min, Max and Period
+func (c *Compensation) Normalize() {
+	c.AnnualMin, c.AnnualMax = nil, nil
+	factor, ok := annualFactor[c.Period]
+	if !ok {
+		return
+	}
+	if c.Min != nil {
+		v := *c.Min * factor
+		c.AnnualMin = &v
+	}
+	if c.Max != nil {
+		v := *c.Max * factor
+		c.AnnualMax = &v
+	}
+}
+
+// IsOpen reports whether the job is published and has not passed its expiry date
+func (j Job) IsOpen(now time.Time) bool {
+	return j.Status == JobPublished && (j.ExpiresAt == nil || j.ExpiresAt.After(now))
+}
+
+// Redact removes the amounts of a hidden range and the knockout rules of
+// screening questions before a job is shown publicly
+func (j *Job) Redact() {
+	for i := range j.Questions {
+		j.Questions[i].HideKnockouts()
+	}
+	if !j.Compensation.Hidden {
+		return
+	}
+	j.Salary = ""
+	j.Compensation = Compensation{Hidden: true}
+}
+
+// LocationLabel lists the places a job can be done from, falling back to the
+// free-form location
+func (j Job) LocationLabel() string {
+	var labels []string
+	for _, location := range j.Locations {
+		labels = append(labels, location.Label())
+	}
+	if len(labels) == 0 {
+		return j.Location
+	}
+	return strings.Join(labels, "; ")
+}
+
+// SalaryLabel describes the pay of a job such as "90000-110000 EUR yearly",
+// preferring the structured range over the free-form salary
+func (j Job) SalaryLabel() string {
+	comp := j.Compensation
+	if comp.Hidden || comp.IsZero() {
+		return j.Salary
+	}
+	var amount string
+	switch {
+	case comp.Min != nil && comp.Max != nil && *comp.Min != *comp.Max:
+		amount = fmt.Sprintf("%d-%d", *comp.Min, *comp.Max)
+	case comp.Min != nil:
+		amount = fmt.Sprintf("%d", *comp.Min)
+	default:
+		amount = fmt.Sprintf("%d", *comp.Max)
+	}
+	label := strings.TrimSpace(amount + " " + comp.Currency)
+	if comp.Period != "" {
+		label += " " + comp.Period
+	}
+	return label
 }
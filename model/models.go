@@ -0,0 +1,49 @@
+package model
+
+// AllModels lists every model managed by migrations, in creation order
+func AllModels() []interface{} {
+	return []interface{}{
+		&User{},
+		&Experience{},
+		&Education{},
+		&Post{},
+		&Company{},
+		&JobCategory{},
+		&Job{},
+		&JobLocation{},
+		&ScreeningQuestion{},
+		&ScreeningOption{},
+		&PipelineTemplate{},
+		&PipelineStage{},
+		&SkillCategory{},
+		&Skill{},
+		&SkillAlias{},
+		&UserFollow{},
+		&Like{},
+		&Comment{},
+		&Application{},
+		&ScreeningAnswer{},
+		&Document{},
+		&ApplicationStatusChange{},
+		&Interviewer{},
+		&Interview{},
+		&InterviewSlot{},
+		&AuditLog{},
+		&SavedSearch{},
+		&Notification{},
+	}
+}
+
+// Schema objects the migrate tool creates with SQL rather than AutoMigrate.
+// Readiness probes check them together with the models.
+var (
+	MigratedColumns  = map[string][]string{"jobs": {"search_vector"}}
+	MigratedIndexes  = []string{"idx_jobs_search_vector", "idx_skills_key"}
+	MigratedTriggers = []string{
+		"jobs_search_vector_trigger",
+		"job_skills_search_trigger",
+		"skills_search_trigger",
+		"skill_aliases_search_trigger",
+		"companies_search_trigger",
+	}
+)
@@ -0,0 +1,26 @@
+package model
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Notification types
+const (
+	NotificationJobAlert  = "job_alert"
+	NotificationInterview = "interview"
+)
+
+// Notification is an entry in a user's in-app notification center
+type Notification struct {
+	gorm.Model
+	UserID uint       `json:"user_id" gorm:"not null;index"`
+	User   User       `json:"-" gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE"`
+	Type   string     `json:"type" gorm:"not null"`
+	Title  string     `json:"title" gorm:"not null"`
+	Body   string     `json:"body"`
+	Link   string     `json:"link"`
+	Data   JSON       `json:"data" gorm:"type:jsonb"`
+	ReadAt *time.Time `json:"read_at" gorm:"index"`
+}
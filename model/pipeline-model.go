@@ -0,0 +1,30 @@
+package model
+
+import "gorm.io/gorm"
+
+// PipelineTemplate is a reusable list of hiring stages a company can copy
+// onto its jobs
+type PipelineTemplate struct {
+	gorm.Model
+	CompanyID uint            `json:"company_id" gorm:"not null;index"`
+	Name      string          `json:"name" gorm:"not null"`
+	Stages    []PipelineStage `json:"stages" gorm:"foreignKey:TemplateID;constraint:OnDelete:CASCADE"`
+}
+
+// PipelineStage is one step of a hiring pipeline, belonging either to a
+// template or to a job. Category is the application state the stage stands
+// for, so moving an application between stages of different categories is a
+// status change and follows the same transitions.
+type PipelineStage struct {
+	gorm.Model
+	TemplateID *uint  `json:"template_id,omitempty" gorm:"index"`
+	JobID      *uint  `json:"job_id,omitempty" gorm:"index"`
+	Position   int    `json:"position" gorm:"not null;default:0"`
+	Name       string `json:"name" gorm:"not null"`
+	Category   string `json:"category" gorm:"not null"`
+}
+
+// IsTerminal reports whether applications in the stage are out of the pipeline
+func (s PipelineStage) IsTerminal() bool {
+	return IsTerminalApplicationStatus(s.Category)
+}
@@ -0,0 +1,32 @@
+package model
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Alert frequencies for saved searches
+const (
+	AlertInstant = "instant"
+	AlertDaily   = "daily"
+	AlertWeekly  = "weekly"
+)
+
+// SavedSearch is a job search a user wants to be alerted about. Query is the
+// GET /job/ query string as entered; Filters is its parsed form.
+type SavedSearch struct {
+	gorm.Model
+	UserID           uint      `json:"user_id" gorm:"not null;index"`
+	User             User      `json:"-" gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE"`
+	Name             string    `json:"name" gorm:"not null"`
+	Query            string    `json:"query"`
+	Filters          JSON      `json:"filters" gorm:"type:jsonb;not null"`
+	Frequency        string    `json:"frequency" gorm:"not null;default:'daily'"`
+	EmailEnabled     bool      `json:"email_enabled" gorm:"not null;default:true"`
+	Active           bool      `json:"active" gorm:"not null;default:true;index"`
+	UnsubscribeToken string    `json:"-" gorm:"not null;uniqueIndex"`
+	LastCheckedAt    time.Time `json:"last_checked_at"`
+	// The alert notification whose email has not been sent yet
+	PendingAlertID *uint `json:"-"`
+}
@@ -0,0 +1,63 @@
+package model
+
+import "gorm.io/gorm"
+
+// Screening question types
+const (
+	QuestionText         = "text"
+	QuestionYesNo        = "yes_no"
+	QuestionSingleChoice = "single_choice"
+	QuestionMultiChoice  = "multi_choice"
+	QuestionNumber       = "number"
+)
+
+// QuestionTypes lists every screening question type
+var QuestionTypes = []string{QuestionText, QuestionYesNo, QuestionSingleChoice, QuestionMultiChoice, QuestionNumber}
+
+// ScreeningQuestion is a question applicants to a job must answer. Knockout
+// rules reject an application automatically: KnockoutAnswer ("yes" or "no")
+// for yes/no questions, Knockout options for choice questions, and answers
+// outside KnockoutMin..KnockoutMax for numeric ones.
+type ScreeningQuestion struct {
+	gorm.Model
+	JobID          uint              `json:"job_id" gorm:"not null;index"`
+	Position       int               `json:"position" gorm:"not null;default:0"`
+	Prompt         string            `json:"prompt" gorm:"not null"`
+	Type           string            `json:"type" gorm:"not null"`
+	Required       bool              `json:"required" gorm:"not null;default:false"`
+	Options        []ScreeningOption `json:"options,omitempty" gorm:"foreignKey:QuestionID;constraint:OnDelete:CASCADE"`
+	KnockoutAnswer string            `json:"knockout_answer,omitempty" gorm:"not null;default:''"`
+	KnockoutMin    *float64          `json:"knockout_min,omitempty"`
+	KnockoutMax    *float64          `json:"knockout_max,omitempty"`
+}
+
+// ScreeningOption is one of the choices of a choice question
+type ScreeningOption struct {
+	gorm.Model
+	QuestionID uint   `json:"question_id" gorm:"not null;index"`
+	Position   int    `json:"position" gorm:"not null;default:0"`
+	Label      string `json:"label" gorm:"not null"`
+	Knockout   bool   `json:"knockout,omitempty" gorm:"not null;default:false"`
+}
+
+// ScreeningAnswer is an applicant's answer to a screening question. Prompt
+// and Type are copied from the question so answers stay readable after the
+// job's questions are edited. Value holds a string, boolean, number or list
+// of option labels depending on Type.
+type ScreeningAnswer struct {
+	gorm.Model
+	ApplicationID uint   `json:"application_id" gorm:"not null;index"`
+	QuestionID    uint   `json:"question_id" gorm:"not null;index"`
+	Prompt        string `json:"prompt" gorm:"not null"`
+	Type          string `json:"type" gorm:"not null"`
+	Value         JSON   `json:"value" gorm:"type:jsonb"`
+	Knockout      bool   `json:"knockout" gorm:"not null;default:false"`
+}
+
+// HideKnockouts removes the knockout rules so questions can be shown to applicants
+func (q *ScreeningQuestion) HideKnockouts() {
+	q.KnockoutAnswer, q.KnockoutMin, q.KnockoutMax = "", nil, nil
+	for i := range q.Options {
+		q.Options[i].Knockout = false
+	}
+}
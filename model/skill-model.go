@@ -1,11 +1,47 @@
 package model
 
-import "gorm.io/gorm"
+import (
+	"strings"
+
+	"gorm.io/gorm"
+)
 
 type Skill struct {
 	gorm.Model
-	Name        string       `json:"name" gorm:"unique;not null"`
-	Users       []User       `gorm:"many2many:user_skills"`
-	Jobs        []Job        `gorm:"many2many:job_skills"`
-	Experiences []Experience `gorm:"many2many:experience_skills"`
+	Name string `json:"name" gorm:"unique;not null"`
+	// Normalized name, see SkillKey. Unique among live skills (index created by migrate).
+	Key         string         `json:"-" gorm:"not null;default:''"`
+	CategoryID  *uint          `json:"category_id" gorm:"index"`
+	Category    *SkillCategory `json:"category,omitempty" gorm:"foreignKey:CategoryID;constraint:OnDelete:SET NULL"`
+	Aliases     []SkillAlias   `json:"aliases,omitempty" gorm:"foreignKey:SkillID;constraint:OnDelete:CASCADE"`
+	Users       []User         `gorm:"many2many:user_skills"`
+	Jobs        []Job          `gorm:"many2many:job_skills"`
+	Experiences []Experience   `gorm:"many2many:experience_skills"`
+}
+
+// SkillAlias is another name for a skill, such as "golang" for Go
+type SkillAlias struct {
+	gorm.Model
+	SkillID uint   `json:"skill_id" gorm:"not null;index"`
+	Name    string `json:"name" gorm:"not null"`
+	Key     string `json:"-" gorm:"not null;uniqueIndex"`
+}
+
+// SkillCategory groups skills, e.g. Languages > Go. Categories can nest.
+type SkillCategory struct {
+	gorm.Model
+	Name     string          `json:"name" gorm:"unique;not null"`
+	ParentID *uint           `json:"parent_id" gorm:"index"`
+	Children []SkillCategory `json:"children,omitempty" gorm:"foreignKey:ParentID;constraint:OnDelete:SET NULL"`
+}
+
+// SkillKey normalizes a skill name for matching: case-folded with runs of
+// whitespace collapsed, so "  Machine   learning" and "machine learning" agree
+func SkillKey(name string) string {
+	return strings.ToLower(strings.Join(strings.Fields(name), " "))
+}
+
+// CleanSkillName collapses whitespace in a skill name without changing its case
+func CleanSkillName(name string) string {
+	return strings.Join(strings.Fields(name), " ")
 }
@@ -0,0 +1,36 @@
+package model
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"strings"
+)
+
+// StringList is a list of short codes stored as a comma-separated string
+type StringList []string
+
+// Value implements driver.Valuer
+func (l StringList) Value() (driver.Value, error) {
+	return strings.Join(l, ","), nil
+}
+
+// Scan implements sql.Scanner
+func (l *StringList) Scan(value interface{}) error {
+	var s string
+	switch v := value.(type) {
+	case nil:
+	case string:
+		s = v
+	case []byte:
+		s = string(v)
+	default:
+		return fmt.Errorf("cannot scan %T into StringList", value)
+	}
+	*l = nil
+	for _, item := range strings.Split(s, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			*l = append(*l, item)
+		}
+	}
+	return nil
+}
@@ -14,12 +14,21 @@ func ApplicationRoutes(r *gin.Engine) {
 	application.GET("/user", middleware.RequireAuth, controller.GetUserApplications)
 	// ROUTE FOR USERS TO GET A PARTICULAR APPLICATION
 	application.GET("/:id", middleware.RequireAuth, controller.GetApplicationByID)
-	// ROUTE FOR USERS TO DELETE THEIR APPLICATIONS
-	application.DELETE("/:id", middleware.RequireAuth, controller.DeleteApplication)
+	// ROUTE FOR USERS TO GET THE STATUS HISTORY OF AN APPLICATION
+	application.GET("/:id/timeline", middleware.RequireAuth, controller.GetApplicationTimeline)
+	// ROUTE FOR USERS TO UPDATE DOCUMENTS AND ANSWERS BEFORE REVIEW
+	application.PUT("/:id", middleware.RequireAuth, controller.UpdateApplication)
+	// ROUTES FOR USERS TO WITHDRAW THEIR APPLICATIONS; DELETE IS KEPT FOR OLDER CLIENTS
+	application.POST("/:id/withdraw", middleware.RequireAuth, controller.WithdrawApplication)
+	application.DELETE("/:id", middleware.RequireAuth, controller.WithdrawApplication)
 	// ROUTE FOR COMPANIES TO GET APPLICATIONS
 	application.GET("/company/:id", middleware.CompanyAuth, controller.GetApplicationsByCompany)
 	// ROUTE FOR COMPANIES TO UPDATE THE STATUS OF APPLICATIONS
 	application.PATCH("/company/:id/status", middleware.CompanyAuth, controller.UpdateApplicationStatusByCompany)
+	// ROUTE FOR COMPANIES TO MOVE AN APPLICATION TO ANOTHER PIPELINE STAGE
+	application.POST("/company/:id/stage", middleware.CompanyAuth, controller.MoveApplicationStage)
+	// ROUTE FOR COMPANIES TO GET THE STATUS HISTORY OF AN APPLICATION
+	application.GET("/company/:id/timeline", middleware.CompanyAuth, controller.GetApplicationTimelineByCompany)
 	// ROUTE FOR COMPANIES TO DELETE APPLICATIONS
 	application.DELETE("/company/:id", middleware.CompanyAuth, controller.DeleteApplicationByCompany)
 }
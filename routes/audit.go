@@ -0,0 +1,12 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/sahilq312/workly/controller"
+	"github.com/sahilq312/workly/middleware"
+)
+
+func AuditRoutes(r *gin.Engine) {
+	audit := r.Group("/audit", middleware.AdminAuth)
+	audit.GET("/", controller.GetAuditLogs)
+}
@@ -0,0 +1,11 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/sahilq312/workly/controller"
+)
+
+// DocumentRoutes needs no session: download links are signed for their principal
+func DocumentRoutes(r *gin.Engine) {
+	r.GET("/document/:id", controller.DownloadDocument)
+}
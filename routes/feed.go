@@ -0,0 +1,14 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/sahilq312/workly/controller"
+)
+
+func FeedRoutes(r *gin.Engine) {
+	feed := r.Group("/feed")
+	feed.GET("/:format", controller.GetJobFeed)
+	feed.GET("/company/:id/:format", controller.GetJobFeed)
+	feed.GET("/skill/:skill/:format", controller.GetJobFeed)
+	feed.GET("/location/:location/:format", controller.GetJobFeed)
+}
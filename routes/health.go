@@ -0,0 +1,12 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/sahilq312/workly/controller"
+)
+
+// HealthRoutes registers unauthenticated probes for orchestrators
+func HealthRoutes(r *gin.Engine) {
+	r.GET("/livez", controller.Livez)
+	r.GET("/readyz", controller.Readyz)
+}
@@ -0,0 +1,28 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/sahilq312/workly/controller"
+	"github.com/sahilq312/workly/middleware"
+)
+
+func InterviewRoutes(r *gin.Engine) {
+	interview := r.Group("/interview")
+	interview.GET("/feed/:token", controller.GetInterviewerFeed)
+
+	company := interview.Group("", middleware.CompanyAuth)
+	company.POST("/interviewer/create", controller.CreateInterviewer)
+	company.GET("/interviewer", controller.GetInterviewers)
+	company.DELETE("/interviewer/delete/:id", controller.DeleteInterviewer)
+	company.POST("/create", controller.CreateInterview)
+	company.GET("/company", controller.GetCompanyInterviews)
+	company.POST("/company/reschedule/:id", controller.RescheduleInterview)
+	company.POST("/company/cancel/:id", controller.CancelInterviewByCompany)
+	company.GET("/company/:id/invite.ics", controller.GetCompanyInterviewInvite)
+
+	user := interview.Group("", middleware.RequireAuth)
+	user.GET("/user", controller.GetUserInterviews)
+	user.POST("/user/book/:id", controller.BookInterview)
+	user.POST("/user/cancel/:id", controller.CancelInterviewByUser)
+	user.GET("/user/:id/invite.ics", controller.GetUserInterviewInvite)
+}
@@ -4,14 +4,22 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/sahilq312/workly/controller"
 	"github.com/sahilq312/workly/middleware"
+	"github.com/sahilq312/workly/model"
 )
 
 func JobRoutes(r *gin.Engine) {
 	job := r.Group("/job")
 	job.GET("/", controller.GetAllJobs)
+	job.GET("/search", controller.SearchJobs)
+	job.GET("/recommendations", middleware.RequireAuth, controller.GetJobRecommendations)
 	job.POST("/create", middleware.CompanyAuth, controller.CreateJob)
+	job.POST("/import", middleware.CompanyAuth, controller.ImportJobs)
+	job.GET("/export", middleware.CompanyAuth, controller.ExportJobs)
 	job.GET("/get/:id", controller.GetJob)
 	job.PUT("/update/:id", middleware.CompanyAuth, controller.UpdateJob)
 	job.DELETE("/delete/:id", middleware.CompanyAuth, controller.DeleteJob)
-	
+	job.POST("/publish/:id", middleware.CompanyAuth, controller.TransitionJob(model.JobPublished, model.JobDraft, model.JobPaused))
+	job.POST("/pause/:id", middleware.CompanyAuth, controller.TransitionJob(model.JobPaused))
+	job.POST("/close/:id", middleware.CompanyAuth, controller.TransitionJob(model.JobClosed))
+	job.POST("/reopen/:id", middleware.CompanyAuth, controller.TransitionJob(model.JobPublished, model.JobClosed, model.JobExpired))
 }
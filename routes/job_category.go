@@ -0,0 +1,15 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/sahilq312/workly/controller"
+	"github.com/sahilq312/workly/middleware"
+)
+
+func JobCategoryRoutes(r *gin.Engine) {
+	category := r.Group("/job-category")
+	category.GET("/", controller.GetJobCategories)
+	category.POST("/create", middleware.AdminAuth, controller.CreateJobCategory)
+	category.PUT("/update/:id", middleware.AdminAuth, controller.UpdateJobCategory)
+	category.DELETE("/delete/:id", middleware.AdminAuth, controller.DeleteJobCategory)
+}
@@ -0,0 +1,14 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/sahilq312/workly/controller"
+	"github.com/sahilq312/workly/middleware"
+)
+
+func NotificationRoutes(r *gin.Engine) {
+	notification := r.Group("/notification", middleware.RequireAuth)
+	notification.GET("/", controller.GetNotifications)
+	notification.POST("/read/:id", controller.MarkNotificationRead)
+	notification.POST("/read-all", controller.MarkAllNotificationsRead)
+}
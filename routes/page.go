@@ -0,0 +1,13 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/sahilq312/workly/controller"
+)
+
+func PageRoutes(r *gin.Engine) {
+	r.GET("/jobs/:company/:slug", controller.GetJobPage)
+	r.GET("/companies/:slug", controller.GetCompanyPage)
+	r.GET("/sitemap.xml", controller.GetSitemap)
+	r.GET("/robots.txt", controller.GetRobots)
+}
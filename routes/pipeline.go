@@ -0,0 +1,18 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/sahilq312/workly/controller"
+	"github.com/sahilq312/workly/middleware"
+)
+
+func PipelineRoutes(r *gin.Engine) {
+	pipeline := r.Group("/pipeline", middleware.CompanyAuth)
+	pipeline.POST("/template/create", controller.CreatePipelineTemplate)
+	pipeline.GET("/template", controller.GetPipelineTemplates)
+	pipeline.PUT("/template/update/:id", controller.UpdatePipelineTemplate)
+	pipeline.DELETE("/template/delete/:id", controller.DeletePipelineTemplate)
+	pipeline.GET("/job/:id", controller.GetJobPipeline)
+	pipeline.PUT("/job/:id", controller.SetJobPipeline)
+	pipeline.GET("/job/:id/board", controller.GetPipelineBoard)
+}
@@ -0,0 +1,18 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/sahilq312/workly/controller"
+	"github.com/sahilq312/workly/middleware"
+)
+
+func SavedSearchRoutes(r *gin.Engine) {
+	savedSearch := r.Group("/saved-search")
+	savedSearch.POST("/create", middleware.RequireAuth, controller.CreateSavedSearch)
+	savedSearch.GET("/", middleware.RequireAuth, controller.GetSavedSearches)
+	savedSearch.PUT("/update/:id", middleware.RequireAuth, controller.UpdateSavedSearch)
+	savedSearch.DELETE("/delete/:id", middleware.RequireAuth, controller.DeleteSavedSearch)
+	// Links in alert emails; no login required. GET only confirms.
+	savedSearch.GET("/unsubscribe/:token", controller.GetUnsubscribeSavedSearch)
+	savedSearch.POST("/unsubscribe/:token", controller.UnsubscribeSavedSearch)
+}
@@ -0,0 +1,21 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/sahilq312/workly/controller"
+	"github.com/sahilq312/workly/middleware"
+)
+
+func SkillRoutes(r *gin.Engine) {
+	skill := r.Group("/skill")
+	skill.GET("/", controller.GetSkills)
+	skill.GET("/autocomplete", controller.AutocompleteSkills)
+	skill.GET("/categories", controller.GetSkillCategories)
+
+	// Taxonomy maintenance
+	skill.POST("/categories", middleware.AdminAuth, controller.CreateSkillCategory)
+	skill.PUT("/update/:id", middleware.AdminAuth, controller.UpdateSkill)
+	skill.POST("/merge", middleware.AdminAuth, controller.MergeSkills)
+	skill.POST("/alias/:id", middleware.AdminAuth, controller.AddSkillAlias)
+	skill.DELETE("/alias/:id", middleware.AdminAuth, controller.DeleteSkillAlias)
+}
@@ -0,0 +1,339 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"math/rand"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/sahilq312/workly/helpers"
+	"github.com/sahilq312/workly/initializer"
+	"github.com/sahilq312/workly/model"
+	"github.com/sahilq312/workly/utils"
+	"gorm.io/gorm"
+)
+
+// Password shared by every seeded user and company
+const seedPassword = "password123"
+
+var (
+	firstNames = []string{"Aarav", "Maya", "Liam", "Sofia", "Noah", "Priya", "Ethan", "Zara", "Lucas", "Emma", "Kabir", "Ava", "Mateo", "Isha", "Oliver", "Chloe"}
+	lastNames  = []string{"Sharma", "Garcia", "Smith", "Khan", "Müller", "Rossi", "Tanaka", "Nguyen", "Dubois", "Silva", "Patel", "Johnson"}
+	cities     = []string{"Bengaluru", "Berlin", "London", "San Francisco", "New York", "Toronto", "Amsterdam", "Singapore", "Remote"}
+	schools    = []string{"IIT Delhi", "TU Munich", "University of Toronto", "MIT", "ETH Zurich", "NUS", "Imperial College London"}
+	degrees    = []string{"B.Tech", "B.Sc", "M.Sc", "MBA", "PhD"}
+	fields     = []string{"Computer Science", "Electrical Engineering", "Mathematics", "Design", "Economics"}
+	companies  = []string{"Acme", "Globex", "Initech", "Umbrella", "Hooli", "Stark Industries", "Wayne Enterprises", "Pied Piper", "Vandelay", "Soylent"}
+	jobTitles  = []string{"Backend Engineer", "Frontend Engineer", "Full Stack Developer", "Data Engineer", "DevOps Engineer", "Product Designer", "Engineering Manager", "QA Engineer", "Mobile Developer", "Site Reliability Engineer"}
+	levels     = []string{"Junior", "", "Senior", "Staff"}
+
+	seniorityByLevel = map[string]string{"Junior": model.SeniorityJunior, "": model.SeniorityMid, "Senior": model.SenioritySenior, "Staff": model.SeniorityLead}
+	categoryByTitle  = map[string]string{
+		"Backend Engineer":          "engineering",
+		"Frontend Engineer":         "engineering",
+		"Full Stack Developer":      "engineering",
+		"Mobile Developer":          "engineering",
+		"Engineering Manager":       "engineering",
+		"Data Engineer":             "data-science",
+		"DevOps Engineer":           "devops-infrastructure",
+		"Site Reliability Engineer": "devops-infrastructure",
+		"Product Designer":          "design",
+		"QA Engineer":               "quality-assurance",
+	}
+	// Mostly full-time, as on a real board
+	employmentTypes = []string{
+		model.EmploymentFullTime, model.EmploymentFullTime, model.EmploymentFullTime, model.EmploymentContract,
+		model.EmploymentFullTime, model.EmploymentPartTime, model.EmploymentFullTime, model.EmploymentInternship,
+		model.EmploymentFullTime, model.EmploymentTemporary,
+	}
+	skillNames = []string{"Go", "Python", "JavaScript", "TypeScript", "React", "PostgreSQL", "Docker", "Kubernetes", "AWS", "GraphQL", "Redis", "Figma", "Java", "Kotlin", "Swift", "Terraform"}
+	postTopics = []string{"Lessons from my first on-call rotation", "Why we moved to Postgres", "Hiring tips for junior engineers", "My favourite Go idioms", "Scaling a team from 5 to 50", "Notes from a design review"}
+	comments   = []string{"Great write-up!", "Thanks for sharing.", "We ran into the same thing.", "Could you expand on this?", "Bookmarked.", "Interesting take."}
+	statuses   = []string{model.ApplicationApplied, model.ApplicationApplied, model.ApplicationScreening, model.ApplicationInterview, model.ApplicationRejected}
+)
+
+type seedConfig struct {
+	Seed                int64
+	Users               int
+	Companies           int
+	JobsPerCompany      int
+	PostsPerUser        int
+	ApplicationsPerUser int
+}
+
+func init() {
+	initializer.LoadDatabaseConfig()
+	// Seeding a large dataset may take longer than any request
+	initializer.Config.Database.StatementTimeout = 0
+	initializer.ConnectPostgresDatabase()
+}
+
+func main() {
+	var cfg seedConfig
+	flag.Int64Var(&cfg.Seed, "seed", 42, "random seed; the same seed always produces the same dataset")
+	flag.IntVar(&cfg.Users, "users", 50, "number of users")
+	flag.IntVar(&cfg.Companies, "companies", 10, "number of companies")
+	flag.IntVar(&cfg.JobsPerCompany, "jobs", 5, "number of jobs per company")
+	flag.IntVar(&cfg.PostsPerUser, "posts", 2, "maximum number of posts per user")
+	flag.IntVar(&cfg.ApplicationsPerUser, "applications", 3, "maximum number of applications per user")
+	flag.Parse()
+	for _, count := range []struct {
+		flag  string
+		value int
+	}{
+		{"users", cfg.Users},
+		{"companies", cfg.Companies},
+		{"jobs", cfg.JobsPerCompany},
+		{"posts", cfg.PostsPerUser},
+		{"applications", cfg.ApplicationsPerUser},
+	} {
+		if count.value < 0 {
+			fmt.Fprintf(flag.CommandLine.Output(), "-%s must not be negative, got %d\n", count.flag, count.value)
+			flag.Usage()
+			os.Exit(2)
+		}
+	}
+
+	start := time.Now()
+	err := initializer.DB.Transaction(func(tx *gorm.DB) error {
+		return seed(tx, cfg)
+	})
+	if err != nil {
+		log.Fatalf("seed failed: %v", err)
+	}
+	log.Printf("seeded %d users and %d companies in %s (password: %q)", cfg.Users, cfg.Companies, time.Since(start).Round(time.Millisecond), seedPassword)
+}
+
+// seed creates the dataset. Every row is looked up by a natural key before it is
+// created, so running it again with the same flags changes nothing. All random
+// choices are drawn up front from rng, independent of what is already stored.
+func seed(tx *gorm.DB, cfg seedConfig) error {
+	rng := rand.New(rand.NewSource(cfg.Seed))
+	base := time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+	password, err := utils.HashPassword(seedPassword)
+	if err != nil {
+		return err
+	}
+
+	skills, err := helpers.ResolveSkills(tx, skillNames)
+	if err != nil {
+		return err
+	}
+
+	// Default categories come from migrate; jobs stay uncategorized without them
+	var jobCategories []model.JobCategory
+	if err := tx.Find(&jobCategories).Error; err != nil {
+		return err
+	}
+	categories := map[string]model.JobCategory{}
+	for _, category := range jobCategories {
+		categories[category.Slug] = category
+	}
+
+	// Companies with jobs
+	var jobs []model.Job
+	for i := 0; i < cfg.Companies; i++ {
+		name := companies[i%len(companies)]
+		if i >= len(companies) {
+			name = fmt.Sprintf("%s %d", name, i/len(companies)+1)
+		}
+		company := model.Company{
+			Name:     name,
+			Email:    fmt.Sprintf("company%d@seed.workly.dev", i+1),
+			Password: password,
+			Address:  pick(rng, cities),
+		}
+		if err := tx.Where(model.Company{Email: company.Email}).Attrs(company).FirstOrCreate(&company).Error; err != nil {
+			return fmt.Errorf("company %d: %w", i+1, err)
+		}
+
+		for j := 0; j < cfg.JobsPerCompany; j++ {
+			level, jobTitle := pick(rng, levels), pick(rng, jobTitles)
+			title := strings.TrimSpace(level + " " + jobTitle)
+			title = fmt.Sprintf("%s (#%d)", title, j+1)
+			low := 40 + rng.Intn(120)
+			publishedAt := base.AddDate(0, 0, rng.Intn(365))
+			job := model.Job{
+				Title:       title,
+				Description: fmt.Sprintf("%s is hiring a %s to join a growing team.", company.Name, title),
+				Location:    pick(rng, cities),
+				Salary:      fmt.Sprintf("%dk-%dk USD", low, low+10+rng.Intn(40)),
+				CompanyID:   company.ID,
+				Status:      model.JobPublished,
+				PublishedAt: &publishedAt,
+				// Derived without the rng so existing seeds keep their titles
+				Seniority:      seniorityByLevel[level],
+				EmploymentType: employmentTypes[(i+j)%len(employmentTypes)],
+			}
+			if category, ok := categories[categoryByTitle[jobTitle]]; ok {
+				job.CategoryID = &category.ID
+			}
+			job.Compensation, _ = helpers.ParseSalary(job.Salary)
+			placement, err := helpers.ResolveJobPlacement("", nil, job.Location, nil)
+			if err != nil {
+				return fmt.Errorf("job %q location: %w", job.Title, err)
+			}
+			job.Location, job.WorkMode, job.Locations = placement.Label, placement.WorkMode, placement.Locations
+			jobSkills := pickSkills(rng, skills, 2+rng.Intn(3))
+			if err := tx.Where(model.Job{CompanyID: company.ID, Title: job.Title}).Attrs(job).FirstOrCreate(&job).Error; err != nil {
+				return fmt.Errorf("job %q: %w", job.Title, err)
+			}
+			if err := tx.Model(&job).Association("Skills").Replace(jobSkills); err != nil {
+				return fmt.Errorf("job %q skills: %w", job.Title, err)
+			}
+			jobs = append(jobs, job)
+		}
+	}
+
+	// Users with profile data
+	users := make([]model.User, cfg.Users)
+	for i := range users {
+		name := fmt.Sprintf("%s %s", pick(rng, firstNames), pick(rng, lastNames))
+		users[i] = model.User{
+			Name:     name,
+			Email:    fmt.Sprintf("user%d@seed.workly.dev", i+1),
+			Password: password,
+		}
+		if err := tx.Where(model.User{Email: users[i].Email}).Attrs(users[i]).FirstOrCreate(&users[i]).Error; err != nil {
+			return fmt.Errorf("user %d: %w", i+1, err)
+		}
+		user := users[i]
+
+		if err := tx.Model(&user).Association("Skills").Replace(pickSkills(rng, skills, 3+rng.Intn(4))); err != nil {
+			return fmt.Errorf("user %d skills: %w", i+1, err)
+		}
+
+		// Work history, most recent last
+		start := base.AddDate(-8+rng.Intn(3), rng.Intn(12), 0)
+		experienceCount := 1 + rng.Intn(3)
+		for e := 0; e < experienceCount; e++ {
+			end := start.AddDate(1+rng.Intn(3), rng.Intn(12), 0)
+			experience := model.Experience{
+				Title:       pick(rng, jobTitles),
+				Company:     pick(rng, companies),
+				Location:    pick(rng, cities),
+				Description: "Shipped features and owned services in production.",
+				StartDate:   start,
+				EndDate:     end,
+				UserID:      user.ID,
+			}
+			expSkills := pickSkills(rng, skills, 1+rng.Intn(3))
+			if err := tx.Where(model.Experience{UserID: user.ID, Title: experience.Title, Company: experience.Company, StartDate: start}).Attrs(experience).FirstOrCreate(&experience).Error; err != nil {
+				return fmt.Errorf("user %d experience: %w", i+1, err)
+			}
+			if err := tx.Model(&experience).Association("Skills").Replace(expSkills); err != nil {
+				return fmt.Errorf("user %d experience skills: %w", i+1, err)
+			}
+			start = end
+		}
+
+		gradYear := 2010 + rng.Intn(14)
+		education := model.Education{
+			School:    pick(rng, schools),
+			Degree:    pick(rng, degrees),
+			Field:     pick(rng, fields),
+			StartDate: time.Date(gradYear-4, time.August, 1, 0, 0, 0, 0, time.UTC),
+			EndDate:   time.Date(gradYear, time.June, 1, 0, 0, 0, 0, time.UTC),
+			UserID:    user.ID,
+		}
+		if err := tx.Where(model.Education{UserID: user.ID, School: education.School, Degree: education.Degree}).Attrs(education).FirstOrCreate(&education).Error; err != nil {
+			return fmt.Errorf("user %d education: %w", i+1, err)
+		}
+	}
+
+	// Social graph: follows, posts, likes and comments
+	var posts []model.Post
+	for i, user := range users {
+		follows := rng.Intn(5)
+		for f := 0; f < follows && len(users) > 1; f++ {
+			other := users[rng.Intn(len(users))]
+			if other.ID == user.ID {
+				continue
+			}
+			follow := model.UserFollow{FollowerID: user.ID, FollowedID: other.ID}
+			if err := tx.Where(follow).FirstOrCreate(&follow).Error; err != nil {
+				return fmt.Errorf("user %d follow: %w", i+1, err)
+			}
+		}
+
+		postCount := rng.Intn(cfg.PostsPerUser + 1)
+		for p := 0; p < postCount; p++ {
+			post := model.Post{
+				Title:   pick(rng, postTopics),
+				Content: "A few thoughts I wanted to share with the community.",
+				UserID:  user.ID,
+			}
+			if err := tx.Where(model.Post{UserID: user.ID, Title: post.Title}).Attrs(post).FirstOrCreate(&post).Error; err != nil {
+				return fmt.Errorf("user %d post: %w", i+1, err)
+			}
+			posts = append(posts, post)
+		}
+	}
+
+	for i, user := range users {
+		if len(posts) == 0 {
+			break
+		}
+		likes := rng.Intn(6)
+		for l := 0; l < likes; l++ {
+			post := posts[rng.Intn(len(posts))]
+			like := model.Like{UserID: user.ID, PostID: post.ID}
+			if err := tx.Where(like).FirstOrCreate(&like).Error; err != nil {
+				return fmt.Errorf("user %d like: %w", i+1, err)
+			}
+		}
+		commentCount := rng.Intn(3)
+		for k := 0; k < commentCount; k++ {
+			post := posts[rng.Intn(len(posts))]
+			comment := model.Comment{UserID: user.ID, PostID: post.ID, Content: pick(rng, comments)}
+			if err := tx.Where(comment).FirstOrCreate(&comment).Error; err != nil {
+				return fmt.Errorf("user %d comment: %w", i+1, err)
+			}
+		}
+	}
+
+	// Applications
+	for i, user := range users {
+		if len(jobs) == 0 {
+			break
+		}
+		applicationCount := rng.Intn(cfg.ApplicationsPerUser + 1)
+		for a := 0; a < applicationCount; a++ {
+			job := jobs[rng.Intn(len(jobs))]
+			application := model.Application{UserID: user.ID, JobID: job.ID, Status: pick(rng, statuses)}
+			result := tx.Where(model.Application{UserID: user.ID, JobID: job.ID}).Attrs(application).FirstOrCreate(&application)
+			if result.Error != nil {
+				return fmt.Errorf("user %d application: %w", i+1, result.Error)
+			}
+			if result.RowsAffected == 0 {
+				continue
+			}
+			change := model.ApplicationStatusChange{ApplicationID: application.ID, ToStatus: application.Status, ActorType: model.ActorSystem}
+			if err := tx.Create(&change).Error; err != nil {
+				return fmt.Errorf("user %d application history: %w", i+1, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+func pick(rng *rand.Rand, values []string) string {
+	return values[rng.Intn(len(values))]
+}
+
+// pickSkills returns n distinct skills in a deterministic order
+func pickSkills(rng *rand.Rand, skills []model.Skill, n int) []model.Skill {
+	if n > len(skills) {
+		n = len(skills)
+	}
+	picked := make([]model.Skill, 0, n)
+	for _, idx := range rng.Perm(len(skills))[:n] {
+		picked = append(picked, skills[idx])
+	}
+	return picked
+}
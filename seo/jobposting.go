@@ -0,0 +1,160 @@
+package seo
+
+import (
+	"encoding/json"
+	"html"
+	"strings"
+	"time"
+
+	"github.com/sahilq312/workly/model"
+)
+
+// schema.org employment types for our employment types
+var schemaEmploymentTypes = map[string]string{
+	model.EmploymentFullTime:   "FULL_TIME",
+	model.EmploymentPartTime:   "PART_TIME",
+	model.EmploymentContract:   "CONTRACTOR",
+	model.EmploymentInternship: "INTERN",
+	model.EmploymentTemporary:  "TEMPORARY",
+}
+
+// schema.org unit texts for pay periods
+var schemaPayUnits = map[string]string{
+	model.PayHourly:  "HOUR",
+	model.PayMonthly: "MONTH",
+	model.PayYearly:  "YEAR",
+}
+
+// JobPosting builds the schema.org JobPosting JSON-LD for an open job. The job
+// needs Company, Skills, Locations and Category loaded and hidden pay
+// redacted; jobURL and companyURL are absolute page URLs.
+func JobPosting(job model.Job, jobURL, companyURL string) ([]byte, error) {
+	posted := job.CreatedAt
+	if job.PublishedAt != nil {
+		posted = *job.PublishedAt
+	}
+	organization := map[string]interface{}{
+		"@type":  "Organization",
+		"name":   job.Company.Name,
+		"sameAs": companyURL,
+	}
+	if job.Company.Logo != "" {
+		organization["logo"] = job.Company.Logo
+	}
+	posting := map[string]interface{}{
+		"@context":           "https://schema.org/",
+		"@type":              "JobPosting",
+		"title":              job.Title,
+		"description":        descriptionHTML(job.Description),
+		"datePosted":         posted.UTC().Format(time.RFC3339),
+		"url":                jobURL,
+		"hiringOrganization": organization,
+		"identifier": map[string]interface{}{
+			"@type": "PropertyValue",
+			"name":  job.Company.Name,
+			"value": job.ID,
+		},
+		"directApply": false,
+	}
+	if job.ExpiresAt != nil {
+		posting["validThrough"] = job.ExpiresAt.UTC().Format(time.RFC3339)
+	}
+	if kind, ok := schemaEmploymentTypes[job.EmploymentType]; ok {
+		posting["employmentType"] = kind
+	}
+	if job.Category != nil {
+		posting["occupationalCategory"] = job.Category.Name
+	}
+	if len(job.Skills) > 0 {
+		names := make([]string, len(job.Skills))
+		for i, skill := range job.Skills {
+			names[i] = skill.Name
+		}
+		posting["skills"] = strings.Join(names, ", ")
+	}
+
+	var places []map[string]interface{}
+	for _, location := range job.Locations {
+		address := map[string]interface{}{"@type": "PostalAddress"}
+		if location.City != "" {
+			address["addressLocality"] = location.City
+		}
+		if location.Region != "" {
+			address["addressRegion"] = location.Region
+		}
+		if location.Country != "" {
+			address["addressCountry"] = location.Country
+		}
+		place := map[string]interface{}{"@type": "Place", "address": address}
+		if location.Latitude != nil && location.Longitude != nil {
+			place["geo"] = map[string]interface{}{
+				"@type":     "GeoCoordinates",
+				"latitude":  *location.Latitude,
+				"longitude": *location.Longitude,
+			}
+		}
+		places = append(places, place)
+	}
+	if len(places) > 0 {
+		posting["jobLocation"] = places
+	}
+	if job.WorkMode == model.WorkRemote {
+		posting["jobLocationType"] = "TELECOMMUTE"
+		var countries []map[string]interface{}
+		for _, country := range job.RemoteCountries {
+			countries = append(countries, map[string]interface{}{"@type": "Country", "name": country})
+		}
+		if len(countries) > 0 {
+			posting["applicantLocationRequirements"] = countries
+		}
+	}
+
+	if comp := job.Compensation; !comp.Hidden && !comp.IsZero() && comp.Currency != "" {
+		value := map[string]interface{}{"@type": "QuantitativeValue"}
+		if comp.Min != nil && comp.Max != nil && *comp.Min != *comp.Max {
+			value["minValue"], value["maxValue"] = *comp.Min, *comp.Max
+		} else if comp.Min != nil {
+			value["value"] = *comp.Min
+		} else {
+			value["value"] = *comp.Max
+		}
+		if unit, ok := schemaPayUnits[comp.Period]; ok {
+			value["unitText"] = unit
+		}
+		posting["baseSalary"] = map[string]interface{}{
+			"@type":    "MonetaryAmount",
+			"currency": comp.Currency,
+			"value":    value,
+		}
+	}
+
+	// json.Marshal escapes <, > and &, so the result is safe inside a script tag
+	return json.Marshal(posting)
+}
+
+// descriptionHTML turns a plain-text description into the simple HTML the
+// description property expects
+func descriptionHTML(text string) string {
+	var b strings.Builder
+	for _, paragraph := range paragraphs(text) {
+		b.WriteString("<p>" + strings.ReplaceAll(html.EscapeString(paragraph), "\n", "<br>") + "</p>")
+	}
+	return b.String()
+}
+
+// Organization builds the schema.org Organization JSON-LD for a company page
+func Organization(company model.Company, url string) ([]byte, error) {
+	organization := map[string]interface{}{
+		"@context": "https://schema.org/",
+		"@type":    "Organization",
+		"name":     company.Name,
+		"url":      url,
+	}
+	if company.Logo != "" {
+		organization["logo"] = company.Logo
+	}
+	if company.Address != "" {
+		organization["address"] = company.Address
+	}
+	return json.Marshal(organization)
+}
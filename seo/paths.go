@@ -0,0 +1,48 @@
+// Package seo builds the public, search-engine facing side of the site: job
+// and company page URLs, schema.org structured data and the sitemap.
+package seo
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/sahilq312/workly/model"
+	"github.com/sahilq312/workly/utils"
+)
+
+// Slugs carry the record ID at the end, so pages stay reachable when a title
+// or company name changes and old links can be redirected to the current one.
+
+// JobPath is the canonical page of a job, such as
+// /jobs/acme/senior-go-engineer-123. The job needs Company loaded.
+func JobPath(job model.Job) string {
+	return "/jobs/" + slugOr(job.Company.Name, "company") + "/" + withID(job.Title, "job", job.ID)
+}
+
+// CompanyPath is the canonical page of a company, such as /companies/acme-7
+func CompanyPath(company model.Company) string {
+	return "/companies/" + withID(company.Name, "company", company.ID)
+}
+
+// SlugID reads the ID from the end of a slug such as "senior-go-engineer-123"
+func SlugID(slug string) (uint, bool) {
+	if i := strings.LastIndexByte(slug, '-'); i >= 0 {
+		slug = slug[i+1:]
+	}
+	id, err := strconv.ParseUint(slug, 10, 32)
+	if err != nil || id == 0 {
+		return 0, false
+	}
+	return uint(id), true
+}
+
+func withID(text, fallback string, id uint) string {
+	return slugOr(text, fallback) + "-" + strconv.FormatUint(uint64(id), 10)
+}
+
+func slugOr(text, fallback string) string {
+	if slug := utils.Slugify(text); slug != "" {
+		return slug
+	}
+	return fallback
+}
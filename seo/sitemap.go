@@ -0,0 +1,35 @@
+package seo
+
+import (
+	"encoding/xml"
+	"time"
+)
+
+// MaxSitemapURLs is the most URLs a single sitemap may list
+const MaxSitemapURLs = 50000
+
+// SitemapURL is one page listed in the sitemap
+type SitemapURL struct {
+	Loc        string `xml:"loc"`
+	LastMod    string `xml:"lastmod,omitempty"`
+	ChangeFreq string `xml:"changefreq,omitempty"`
+}
+
+type urlSet struct {
+	XMLName xml.Name     `xml:"http://www.sitemaps.org/schemas/sitemap/0.9 urlset"`
+	URLs    []SitemapURL `xml:"url"`
+}
+
+// NewSitemapURL describes a page last changed at modified
+func NewSitemapURL(loc string, modified time.Time, changeFreq string) SitemapURL {
+	return SitemapURL{Loc: loc, LastMod: modified.UTC().Format(time.RFC3339), ChangeFreq: changeFreq}
+}
+
+// Sitemap renders the sitemaps.org XML for urls
+func Sitemap(urls []SitemapURL) ([]byte, error) {
+	out, err := xml.MarshalIndent(urlSet{URLs: urls}, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte(xml.Header), out...), nil
+}
@@ -0,0 +1,69 @@
+package seo
+
+import (
+	"embed"
+	"html/template"
+	"strings"
+
+	"github.com/sahilq312/workly/model"
+)
+
+//go:embed templates/*.html
+var templateFiles embed.FS
+
+// Templates holds the server-rendered public pages
+var Templates = template.Must(template.New("").Funcs(template.FuncMap{
+	"paragraphs": paragraphs,
+}).ParseFS(templateFiles, "templates/*.html"))
+
+// Page is the metadata shared by every public page
+type Page struct {
+	Title       string
+	Description string
+	Canonical   string
+	// Keeps pages of closed jobs out of search results
+	NoIndex bool
+	// schema.org JSON-LD, already encoded
+	StructuredData template.JS
+}
+
+// JobPage is the data for the job.html template
+type JobPage struct {
+	Page
+	Job        model.Job
+	CompanyURL string
+	Open       bool
+}
+
+// CompanyPage is the data for the company.html template
+type CompanyPage struct {
+	Page
+	Company model.Company
+	Jobs    []JobLink
+}
+
+// UnsubscribePage is the data for the unsubscribe.html template
+type UnsubscribePage struct {
+	SearchName   string
+	Action       string
+	Unsubscribed bool
+}
+
+// JobLink is a job listed on another page
+type JobLink struct {
+	Title    string
+	URL      string
+	Location string
+	Salary   string
+}
+
+// paragraphs splits plain text on blank lines
+func paragraphs(text string) []string {
+	var out []string
+	for _, paragraph := range strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n\n") {
+		if paragraph = strings.TrimSpace(paragraph); paragraph != "" {
+			out = append(out, paragraph)
+		}
+	}
+	return out
+}
@@ -0,0 +1,212 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/sahilq312/workly/config"
+)
+
+// S3 stores objects in a bucket of an S3-compatible store, signing requests
+// with AWS Signature Version 4. Any store speaking the S3 REST API works,
+// including local stand-ins such as MinIO.
+type S3 struct {
+	cfg      config.S3Config
+	endpoint *url.URL
+	client   *http.Client
+	now      func() time.Time
+}
+
+// NewS3 returns a client for cfg.Bucket. An empty endpoint means AWS S3 in cfg.Region.
+func NewS3(cfg config.S3Config) (*S3, error) {
+	if cfg.Region == "" {
+		cfg.Region = "us-east-1"
+	}
+	endpoint := cfg.Endpoint
+	if endpoint == "" {
+		endpoint = "https://s3." + cfg.Region + ".amazonaws.com"
+	}
+	u, err := url.Parse(endpoint)
+	if err != nil || u.Scheme == "" || u.Host == "" {
+		return nil, fmt.Errorf("storage: invalid S3 endpoint %q", endpoint)
+	}
+	if cfg.Bucket == "" {
+		return nil, fmt.Errorf("storage: S3 bucket is required")
+	}
+	return &S3{cfg: cfg, endpoint: u, client: &http.Client{Timeout: time.Minute}, now: time.Now}, nil
+}
+
+// Put buffers the object to sign its checksum; uploads are small documents
+func (s *S3) Put(ctx context.Context, key string, body io.Reader, size int64, contentType string) error {
+	data, err := io.ReadAll(io.LimitReader(body, size+1))
+	if err != nil {
+		return err
+	}
+	if int64(len(data)) != size {
+		return fmt.Errorf("storage: read %d bytes, expected %d", len(data), size)
+	}
+	req, err := s.request(ctx, http.MethodPut, key, data)
+	if err != nil {
+		return err
+	}
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+	resp, err := s.do(req)
+	if err != nil {
+		return err
+	}
+	return resp.Body.Close()
+}
+
+func (s *S3) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	req, err := s.request(ctx, http.MethodGet, key, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := s.do(req)
+	if err != nil {
+		return nil, err
+	}
+	return resp.Body, nil
+}
+
+func (s *S3) Delete(ctx context.Context, key string) error {
+	req, err := s.request(ctx, http.MethodDelete, key, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := s.do(req)
+	if err == ErrNotFound {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	return resp.Body.Close()
+}
+
+// do sends a signed request, turning error statuses into errors
+func (s *S3) do(req *http.Request) (*http.Response, error) {
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return resp, nil
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, ErrNotFound
+	}
+	detail, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+	return nil, fmt.Errorf("storage: S3 %s %s: %s: %s", req.Method, req.URL.Path, resp.Status, bytes.TrimSpace(detail))
+}
+
+// request builds a signed request for the object under key
+func (s *S3) request(ctx context.Context, method, key string, payload []byte) (*http.Request, error) {
+	if err := validKey(key); err != nil {
+		return nil, err
+	}
+	u := *s.endpoint
+	objectPath := "/" + key
+	if s.cfg.PathStyle {
+		objectPath = "/" + s.cfg.Bucket + objectPath
+	} else {
+		u.Host = s.cfg.Bucket + "." + u.Host
+	}
+	u.Path = strings.TrimSuffix(u.Path, "/") + objectPath
+	u.RawPath = strings.TrimSuffix(s.endpoint.EscapedPath(), "/") + escapePath(objectPath)
+
+	req, err := http.NewRequestWithContext(ctx, method, u.String(), bytes.NewReader(payload))
+	if err != nil {
+		return nil, err
+	}
+	req.ContentLength = int64(len(payload))
+	s.sign(req, payload)
+	return req, nil
+}
+
+// sign adds an AWS Signature Version 4 Authorization header
+func (s *S3) sign(req *http.Request, payload []byte) {
+	now := s.now().UTC()
+	amzDate := now.Format("20060102T150405Z")
+	day := now.Format("20060102")
+	payloadHash := sha256Hex(payload)
+
+	req.Header.Set("X-Amz-Date", amzDate)
+	req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+
+	canonical, signedHeaders := canonicalRequest(req)
+	scope := day + "/" + s.cfg.Region + "/s3/aws4_request"
+	stringToSign := "AWS4-HMAC-SHA256\n" + amzDate + "\n" + scope + "\n" + sha256Hex([]byte(canonical))
+
+	key := hmacSHA256([]byte("AWS4"+s.cfg.SecretKey), day)
+	key = hmacSHA256(key, s.cfg.Region)
+	key = hmacSHA256(key, "s3")
+	key = hmacSHA256(key, "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf("AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		s.cfg.AccessKey, scope, signedHeaders, signature))
+}
+
+// canonicalRequest returns the SigV4 canonical form of a request carrying
+// its X-Amz-Date and X-Amz-Content-Sha256 headers, and the signed header names
+func canonicalRequest(req *http.Request) (string, string) {
+	payloadHash := req.Header.Get("X-Amz-Content-Sha256")
+	signed := map[string]string{
+		"host":                 req.URL.Host,
+		"x-amz-content-sha256": payloadHash,
+		"x-amz-date":           req.Header.Get("X-Amz-Date"),
+	}
+	names := make([]string, 0, len(signed))
+	for name := range signed {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var canonicalHeaders strings.Builder
+	for _, name := range names {
+		canonicalHeaders.WriteString(name + ":" + signed[name] + "\n")
+	}
+	signedHeaders := strings.Join(names, ";")
+
+	return strings.Join([]string{
+		req.Method,
+		req.URL.EscapedPath(),
+		req.URL.Query().Encode(),
+		canonicalHeaders.String(),
+		signedHeaders,
+		payloadHash,
+	}, "\n"), signedHeaders
+}
+
+// escapePath URI-encodes each segment of a path as SigV4 requires
+func escapePath(path string) string {
+	segments := strings.Split(path, "/")
+	for i, segment := range segments {
+		segments[i] = strings.ReplaceAll(url.PathEscape(segment), "+", "%2B")
+	}
+	return strings.Join(segments, "/")
+}
+
+func sha256Hex(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
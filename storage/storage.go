@@ -0,0 +1,50 @@
+// Package storage keeps uploaded files in a local directory or an
+// S3-compatible object store.
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/sahilq312/workly/config"
+)
+
+// ErrNotFound is returned when no object is stored under a key
+var ErrNotFound = errors.New("storage: object not found")
+
+// Storage stores opaque objects under slash-separated keys
+type Storage interface {
+	// Put stores size bytes read from body under key, replacing any existing object
+	Put(ctx context.Context, key string, body io.Reader, size int64, contentType string) error
+	// Get opens the object stored under key; the caller must close it
+	Get(ctx context.Context, key string) (io.ReadCloser, error)
+	// Delete removes the object stored under key; deleting a missing object is not an error
+	Delete(ctx context.Context, key string) error
+}
+
+// New returns the backend selected by cfg.Backend
+func New(cfg config.StorageConfig) (Storage, error) {
+	switch cfg.Backend {
+	case "local":
+		return NewLocal(cfg.LocalDir)
+	case "s3":
+		return NewS3(cfg.S3)
+	}
+	return nil, fmt.Errorf("storage: unknown backend %q", cfg.Backend)
+}
+
+// validKey rejects keys that could escape the storage root
+func validKey(key string) error {
+	if key == "" || strings.HasPrefix(key, "/") || strings.Contains(key, "\\") {
+		return fmt.Errorf("storage: invalid key %q", key)
+	}
+	for _, part := range strings.Split(key, "/") {
+		if part == "" || part == "." || part == ".." {
+			return fmt.Errorf("storage: invalid key %q", key)
+		}
+	}
+	return nil
+}
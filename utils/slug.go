@@ -0,0 +1,30 @@
+package utils
+
+import (
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// Slugify turns text into a lowercase, hyphen-separated ASCII slug such as
+// "senior-backend-engineer". Accents are dropped; other symbols separate words.
+func Slugify(text string) string {
+	var b strings.Builder
+	pendingHyphen := false
+	for _, r := range norm.NFD.String(text) {
+		switch {
+		case unicode.Is(unicode.Mn, r):
+			// Combining accent left over from decomposition
+		case r < unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsDigit(r)):
+			if pendingHyphen && b.Len() > 0 {
+				b.WriteByte('-')
+			}
+			pendingHyphen = false
+			b.WriteRune(unicode.ToLower(r))
+		default:
+			pendingHyphen = true
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,225 @@
+package worker
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/sahilq312/workly/health"
+	"github.com/sahilq312/workly/helpers"
+	"github.com/sahilq312/workly/initializer"
+	"github.com/sahilq312/workly/mailer"
+	"github.com/sahilq312/workly/model"
+	"gorm.io/gorm"
+)
+
+const jobAlertsName = "job-alerts"
+
+// Most jobs listed in a single alert; the rest are reachable through the search link
+const maxAlertJobs = 20
+
+// Alert emails that failed are retried on later runs for this long
+const alertEmailRetryWindow = 24 * time.Hour
+
+// StartJobAlerts matches saved searches against newly published jobs every
+// interval, notifying users whose searches are due, until ctx is cancelled
+func StartJobAlerts(ctx context.Context, interval time.Duration) {
+	health.RegisterWorker(jobAlertsName, interval)
+	go run(ctx, jobAlertsName, interval, sendJobAlerts)
+}
+
+func sendJobAlerts(ctx context.Context) error {
+	now := time.Now()
+	var searches []model.SavedSearch
+	err := initializer.Writer(ctx).
+		Preload("User").
+		Where("active = ?", true).
+		Where("pending_alert_id IS NOT NULL OR frequency = ? OR (frequency = ? AND last_checked_at <= ?) OR (frequency = ? AND last_checked_at <= ?)",
+			model.AlertInstant,
+			model.AlertDaily, now.Add(-24*time.Hour),
+			model.AlertWeekly, now.AddDate(0, 0, -7)).
+		Find(&searches).Error
+	if err != nil {
+		return err
+	}
+
+	sent := 0
+	for _, search := range searches {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if search.PendingAlertID != nil {
+			// New matches wait until the earlier email is out, so it is never
+			// replaced by the next alert
+			if err := retryAlertEmail(ctx, search, now); err != nil {
+				log.Printf("%s: saved search %d: email: %v", jobAlertsName, search.ID, err)
+				continue
+			}
+		}
+		if !alertDue(search, now) {
+			continue
+		}
+		notified, err := alertSavedSearch(ctx, search, now)
+		if err != nil {
+			log.Printf("%s: saved search %d: %v", jobAlertsName, search.ID, err)
+			continue
+		}
+		if notified {
+			sent++
+		}
+	}
+	if sent > 0 {
+		log.Printf("%s: sent %d alerts", jobAlertsName, sent)
+	}
+	return nil
+}
+
+// alertDue reports whether search should be matched against new jobs
+func alertDue(search model.SavedSearch, now time.Time) bool {
+	switch search.Frequency {
+	case model.AlertInstant:
+		return true
+	case model.AlertDaily:
+		return !search.LastCheckedAt.After(now.Add(-24 * time.Hour))
+	case model.AlertWeekly:
+		return !search.LastCheckedAt.After(now.AddDate(0, 0, -7))
+	}
+	return false
+}
+
+// alertSavedSearch notifies the owner of search about jobs published since it
+// was last checked and moves its checkpoint to now
+func alertSavedSearch(ctx context.Context, search model.SavedSearch, now time.Time) (bool, error) {
+	var filter helpers.JobFilter
+	if err := json.Unmarshal(search.Filters, &filter); err != nil {
+		return false, err
+	}
+
+	query := filter.Apply(initializer.Writer(ctx).Model(&model.Job{})).
+		Where("jobs.published_at > ? AND jobs.published_at <= ?", search.LastCheckedAt, now)
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return false, err
+	}
+
+	var jobs []model.Job
+	if total > 0 {
+		if err := query.Preload("Company").Order("jobs.published_at DESC").Limit(maxAlertJobs).Find(&jobs).Error; err != nil {
+			return false, err
+		}
+		return true, notifyJobAlert(ctx, search, jobs, total, now)
+	}
+
+	err := initializer.Writer(ctx).Model(&model.SavedSearch{}).
+		Where("id = ?", search.ID).
+		Update("last_checked_at", now).Error
+	return false, err
+}
+
+// notifyJobAlert saves the in-app notification and moves the checkpoint in
+// one transaction, so a failed email never duplicates the notification. The
+// email is sent afterwards and retried by later runs if it fails.
+func notifyJobAlert(ctx context.Context, search model.SavedSearch, jobs []model.Job, total int64, now time.Time) error {
+	searchURL := helpers.PublicURL("/job/")
+	if search.Query != "" {
+		searchURL += "?" + search.Query
+	}
+
+	title := fmt.Sprintf("%d new jobs for %q", total, search.Name)
+	if total == 1 {
+		title = fmt.Sprintf("1 new job for %q", search.Name)
+	}
+
+	ids := make([]uint, len(jobs))
+	var lines []string
+	for i, job := range jobs {
+		ids[i] = job.ID
+		line := job.Title
+		if job.Company.Name != "" {
+			line += " at " + job.Company.Name
+		}
+		if job.Location != "" {
+			line += " (" + job.Location + ")"
+		}
+		lines = append(lines, fmt.Sprintf("- %s\n  %s", line, helpers.JobURL(job)))
+	}
+	if more := total - int64(len(jobs)); more > 0 {
+		lines = append(lines, fmt.Sprintf("...and %d more", more))
+	}
+
+	data, err := json.Marshal(map[string]interface{}{
+		"saved_search_id": search.ID,
+		"job_ids":         ids,
+		"total":           total,
+	})
+	if err != nil {
+		return err
+	}
+	notification := model.Notification{
+		UserID: search.UserID,
+		Type:   model.NotificationJobAlert,
+		Title:  title,
+		Body:   strings.Join(lines, "\n"),
+		Link:   searchURL,
+		Data:   model.JSON(data),
+	}
+	sendEmail := search.EmailEnabled && search.User.Email != ""
+	err = initializer.Writer(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&notification).Error; err != nil {
+			return err
+		}
+		updates := map[string]interface{}{"last_checked_at": now}
+		if sendEmail {
+			updates["pending_alert_id"] = notification.ID
+		}
+		return tx.Model(&model.SavedSearch{}).Where("id = ?", search.ID).Updates(updates).Error
+	})
+	if err != nil || !sendEmail {
+		return err
+	}
+	return sendAlertEmail(ctx, search, notification)
+}
+
+// retryAlertEmail resends the email of an alert whose first send failed,
+// giving up once the alert is older than alertEmailRetryWindow
+func retryAlertEmail(ctx context.Context, search model.SavedSearch, now time.Time) error {
+	var notification model.Notification
+	err := initializer.Writer(ctx).First(&notification, *search.PendingAlertID).Error
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return err
+	}
+	if err != nil || !search.EmailEnabled || search.User.Email == "" || now.Sub(notification.CreatedAt) > alertEmailRetryWindow {
+		return clearPendingAlert(ctx, search)
+	}
+	return sendAlertEmail(ctx, search, notification)
+}
+
+// sendAlertEmail emails an alert notification and clears it from the search
+func sendAlertEmail(ctx context.Context, search model.SavedSearch, notification model.Notification) error {
+	unsubscribeURL := helpers.PublicURL("/saved-search/unsubscribe/" + search.UnsubscribeToken)
+	body := fmt.Sprintf("Hi %s,\n\n%s:\n\n%s\n\nSee all matching jobs: %s\n\nTo stop these alerts, visit %s\n",
+		search.User.Name, notification.Title, notification.Body, notification.Link, unsubscribeURL)
+	err := initializer.Mailer.Send(mailer.Message{
+		To:      search.User.Email,
+		Subject: notification.Title,
+		Body:    body,
+		Headers: map[string]string{
+			"List-Unsubscribe":      "<" + unsubscribeURL + ">",
+			"List-Unsubscribe-Post": "List-Unsubscribe=One-Click",
+		},
+	})
+	if err != nil {
+		return err
+	}
+	return clearPendingAlert(ctx, search)
+}
+
+func clearPendingAlert(ctx context.Context, search model.SavedSearch) error {
+	return initializer.Writer(ctx).Model(&model.SavedSearch{}).
+		Where("id = ?", search.ID).
+		Update("pending_alert_id", nil).Error
+}
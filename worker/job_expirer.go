@@ -0,0 +1,55 @@
+package worker
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/sahilq312/workly/health"
+	"github.com/sahilq312/workly/helpers"
+	"github.com/sahilq312/workly/initializer"
+	"github.com/sahilq312/workly/model"
+)
+
+const jobExpirerName = "job-expirer"
+
+// StartJobExpirer moves published jobs past their expiry date to expired,
+// checking every interval until ctx is cancelled
+func StartJobExpirer(ctx context.Context, interval time.Duration) {
+	health.RegisterWorker(jobExpirerName, interval)
+	go run(ctx, jobExpirerName, interval, expireJobs)
+}
+
+func expireJobs(ctx context.Context) error {
+	var jobs []model.Job
+	now := time.Now()
+	err := initializer.Writer(ctx).
+		Where("status = ? AND expires_at IS NOT NULL AND expires_at <= ?", model.JobPublished, now).
+		Find(&jobs).Error
+	if err != nil {
+		return err
+	}
+
+	for _, job := range jobs {
+		result := initializer.Writer(ctx).Model(&model.Job{}).
+			Where("id = ? AND status = ?", job.ID, model.JobPublished).
+			Update("status", model.JobExpired)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			continue
+		}
+		helpers.RecordAudit(nil, helpers.AuditEntry{
+			Action:     model.AuditJobStatus,
+			EntityType: "job",
+			EntityID:   job.ID,
+			Before:     map[string]string{"status": model.JobPublished},
+			After:      map[string]string{"status": model.JobExpired},
+		})
+	}
+	if len(jobs) > 0 {
+		log.Printf("%s: expired %d jobs", jobExpirerName, len(jobs))
+	}
+	return nil
+}
@@ -0,0 +1,29 @@
+package worker
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/sahilq312/workly/health"
+)
+
+// run calls task once immediately and then every interval, reporting each
+// run to the health registry, until ctx is cancelled
+func run(ctx context.Context, name string, interval time.Duration, task func(context.Context) error) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		err := task(ctx)
+		if err != nil && ctx.Err() == nil {
+			log.Printf("%s: %v", name, err)
+		}
+		health.Beat(name, err)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}